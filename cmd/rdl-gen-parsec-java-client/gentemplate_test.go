@@ -33,7 +33,7 @@ func TestGenerateInterface(test *testing.T) {
 
 	buf := new (bytes.Buffer)
 	writer := bufio.NewWriter(buf)
-	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false}
+	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false, false, false}
 	gen.processTemplate(javaClientInterfaceTemplate)
 	writer.Flush()
 	realClientInterface := buf.String()
@@ -62,7 +62,7 @@ func TestGenerateImpl(test *testing.T) {
 
 	buf := new (bytes.Buffer)
 	writer := bufio.NewWriter(buf)
-	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false}
+	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false, false, false}
 	gen.processTemplate(javaClientTemplate)
 	writer.Flush()
 	realClientImpl := buf.String()
@@ -73,6 +73,35 @@ func TestGenerateImpl(test *testing.T) {
 	}
 }
 
+func TestGenerateImplJakarta(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/sample.json")
+	if err != nil {
+		test.Error("can not read sample file ")
+		os.Exit(1)
+	}
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		test.Error("unmarshal sample data fail")
+		os.Exit(1)
+	}
+
+	reg := rdl.NewTypeRegistry(&schema)
+	cName := utils.Capitalize(string(schema.Name))
+
+	buf := new (bytes.Buffer)
+	writer := bufio.NewWriter(buf)
+	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false, true, false}
+	gen.processTemplate(javaClientTemplate)
+	writer.Flush()
+	realClientImpl := buf.String()
+	expectedSampleClientImpl, err := ioutil.ReadFile("../../testdata/SampleClientImplJakarta.txt")
+	if realClientImpl != string(expectedSampleClientImpl) {
+		test.Errorf("sample jakarta client impl not generated as expected, real: \n%s\n, expected: \n%s\n",
+			realClientImpl, expectedSampleClientImpl)
+	}
+}
+
 func TestGenerateImpl2(test *testing.T) {
 	data, err := ioutil.ReadFile("../../testdata/sample2.json")
 	if err != nil {
@@ -91,7 +120,7 @@ func TestGenerateImpl2(test *testing.T) {
 
 	buf := new (bytes.Buffer)
 	writer := bufio.NewWriter(buf)
-	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false}
+	gen := &javaClientGenerator{reg, &schema, cName, writer, nil, "test", "", "", false, false, false}
 	gen.processTemplate(javaClientTemplate)
 	writer.Flush()
 	realClientImpl := buf.String()
@@ -103,7 +132,7 @@ func TestGenerateImpl2(test *testing.T) {
 }
 
 func TestUriConstruct(test *testing.T) {
-	gen := &javaClientGenerator{nil, nil, "", nil, nil, "test", "", "", false}
+	gen := &javaClientGenerator{nil, nil, "", nil, nil, "test", "", "", false, false, false}
 	inputs := []*rdl.ResourceInput{{Name: "id", PathParam: true}}
 	r := &rdl.Resource{Inputs: inputs}
 	realOut := gen.builderExt(r)
@@ -115,6 +144,26 @@ func TestUriConstruct(test *testing.T) {
 	}
 }
 
+func TestGenerateClientValueTypes(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaClient("valueTypes", schema, testOutputDir, string(schema.Namespace), "", false, false, true)
+
+	//asserts
+	clientContent := string(checkAndGetFileContent(t, path, "SampleClient.java"))
+	assert.Contains(t, clientContent, "CompletableFuture<Item> getItem(Sku sku, Quantity quantity, Price price)")
+	assert.Contains(t, clientContent, "CompletableFuture<Cart> getCart(Skus skus)")
+
+	//clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
 func TestGenerateClientWithoutVersion(t *testing.T) {
 	testOutputDir := getTempDir(t, ".", "testOutput-")
 	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
@@ -124,7 +173,7 @@ func TestGenerateClientWithoutVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	GenerateJavaClient("withoutVersion", schema, testOutputDir, string(schema.Namespace), "", false)
+	GenerateJavaClient("withoutVersion", schema, testOutputDir, string(schema.Namespace), "", false, false, false)
 
 	//asserts
 	clientContent := checkAndGetFileContent(t, path, "SampleClient.java")
@@ -148,7 +197,7 @@ func TestGenerateClientWithVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	GenerateJavaClient("withVersion", schema, testOutputDir, string(schema.Namespace), "", false)
+	GenerateJavaClient("withVersion", schema, testOutputDir, string(schema.Namespace), "", false, false, false)
 
 	//asserts
 	clientContent := checkAndGetFileContent(t, path, "SampleClient.java")
@@ -163,6 +212,32 @@ func TestGenerateClientWithVersion(t *testing.T) {
 	defer os.RemoveAll(testOutputDir)
 }
 
+func TestGenerateClientDeprecation(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleDeprecation.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaClient("deprecation", schema, testOutputDir, string(schema.Namespace), "", false, false, false)
+
+	//asserts
+	clientContent := string(checkAndGetFileContent(t, path, "SampleClient.java"))
+	assert.Contains(t, clientContent, "    /**\n     * @deprecated Use getItem instead. Sunset on 2027-06-30.\n     */\n"+
+		"    @Deprecated\n    CompletableFuture<Item> getLegacyItem(String code)")
+	assert.Contains(t, clientContent, "    @Deprecated\n    CompletableFuture<Item> getLegacyItem(Map<String, List<String>> headers, String code)")
+	assert.Contains(t, clientContent, "headers, String code) throws ResourceException;\n    CompletableFuture<Item> getItem(String sku)")
+
+	clientImplContent := string(checkAndGetFileContent(t, path, "SampleClientImpl.java"))
+	assert.Contains(t, clientImplContent, "    @Override\n    @Deprecated\n    public CompletableFuture<Item> getLegacyItem(String code)")
+	assert.Contains(t, clientImplContent, "    @Override\n    public CompletableFuture<Item> getItem(String sku)")
+
+	//clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
 func checkAndGetFileContent(t *testing.T, path string, fileName string) []byte {
 	//1. check correspanding client file exists
 	if _, err := os.Stat(path + fileName); err != nil {
@@ -27,7 +27,9 @@ type javaClientGenerator struct {
 	banner     string
 	ns         string
 	base       string
-	isPcSuffix bool
+	isPcSuffix    bool
+	jakarta       bool
+	genValueTypes bool
 }
 
 func main() {
@@ -35,10 +37,16 @@ func main() {
 	flag.String("s", "", "RDL source file")
 	namespace := flag.String("ns", "", "Namespace")
 	pc := flag.String("pc", "false", "add '_Pc' postfix to the generated java class")
+	jakartaString := flag.String("jakarta", "false", utils.JakartaFlagUsage)
+	vt := flag.String("vt", "false", "use the value types of the named array, map, string and number types, as generated by parsec-java-model -vt")
 	flag.Parse()
 
 	isPcSuffix, err := strconv.ParseBool(*pc)
 	checkErr(err)
+	jakarta, err := strconv.ParseBool(*jakartaString)
+	checkErr(err)
+	genValueTypes, err := strconv.ParseBool(*vt)
+	checkErr(err)
 
 	data, err := ioutil.ReadAll(os.Stdin)
 	banner := "parsec-rdl-gen (development version)"
@@ -47,7 +55,7 @@ func main() {
 		var schema rdl.Schema
 		err = json.Unmarshal(data, &schema)
 		if err == nil {
-			GenerateJavaClient(banner, &schema, *pOutdir, *namespace, "", isPcSuffix)
+			GenerateJavaClient(banner, &schema, *pOutdir, *namespace, "", isPcSuffix, jakarta, genValueTypes)
 			os.Exit(0)
 		}
 	}
@@ -63,7 +71,7 @@ func checkErr(err error) {
 }
 
 // GenerateJavaClient generates the client code to talk to the server
-func GenerateJavaClient(banner string, schema *rdl.Schema, outdir string, ns string, base string, isPcSuffix bool) error {
+func GenerateJavaClient(banner string, schema *rdl.Schema, outdir string, ns string, base string, isPcSuffix bool, jakarta bool, genValueTypes bool) error {
 
 	reg := rdl.NewTypeRegistry(schema)
 
@@ -78,7 +86,7 @@ func GenerateJavaClient(banner string, schema *rdl.Schema, outdir string, ns str
 	if err != nil {
 		return err
 	}
-	gen := &javaClientGenerator{reg, schema, cName, out, nil, banner, ns, base, isPcSuffix}
+	gen := &javaClientGenerator{reg, schema, cName, out, nil, banner, ns, base, isPcSuffix, jakarta, genValueTypes}
 	gen.processTemplate(javaClientTemplate)
 	out.Flush()
 	file.Close()
@@ -90,7 +98,7 @@ func GenerateJavaClient(banner string, schema *rdl.Schema, outdir string, ns str
 	if err != nil {
 		return err
 	}
-	gen = &javaClientGenerator{reg, schema, cName, out, nil, banner, ns, base, isPcSuffix}
+	gen = &javaClientGenerator{reg, schema, cName, out, nil, banner, ns, base, isPcSuffix, jakarta, genValueTypes}
 	gen.processTemplate(javaClientInterfaceTemplate)
 	out.Flush()
 	file.Close()
@@ -151,6 +159,7 @@ func (gen *javaClientGenerator) processTemplate(templateSource string) error {
 	funcMap := template.FuncMap{
 		"header":      func() string { return utils.JavaGenerationHeader(gen.banner) },
 		"package":     func() string { return utils.JavaGenerationPackage(gen.schema, gen.ns) },
+		"javaee":      func() string { return utils.JavaEENamespace(gen.jakarta) },
 		"comment":     commentFun,
 		"methodSigWithHeader":
 		               func(r *rdl.Resource) string { return "public "+ gen.clientMethodSignature(r, true) },
@@ -172,6 +181,8 @@ func (gen *javaClientGenerator) processTemplate(templateSource string) error {
 		"needExpect":  needExpectFunc,
 		"needImportHashSet":  needImportHashSetFunc,
 		"needImportJsonProcessingException": needImportJsonProcessingExceptionFunc,
+		"deprecation": func(r *rdl.Resource) string { return utils.JavaDeprecation(r.Annotations, 4) },
+		"deprecated":  func(r *rdl.Resource) bool { return utils.IsDeprecated(r.Annotations) },
 	}
 	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(templateSource))
 	return t.Execute(gen.writer, gen.schema)
@@ -228,8 +239,8 @@ import {{package}}.ResourceException;
 
 public interface {{cName}}Client {
 {{range .Resources}}
-    {{iMethod .}}
-    {{iMethodWithHeader .}}{{end}}
+{{deprecation .}}    {{iMethod .}}
+{{deprecation .}}    {{iMethodWithHeader .}}{{end}}
 }
 `
 const javaClientTemplate = `{{origHeader}}
@@ -249,7 +260,7 @@ import com.fasterxml.jackson.databind.ObjectMapper;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
 
-import javax.ws.rs.core.UriBuilder;
+import {{javaee}}.ws.rs.core.UriBuilder;
 import java.net.URI;
 {{if needImportHashSet .Resources}}import java.util.HashSet;
 import java.util.Set;{{end}}
@@ -354,12 +365,14 @@ public class {{cName}}ClientImpl implements {{cName}}Client {
         return defaultHeaders;
     }
 {{range .Resources}}
-    @Override
+    @Override{{if deprecated .}}
+    @Deprecated{{end}}
     {{methodSig .}} {
         {{ContentOfNoHeaderMethod .}}
     }
 
-    @Override
+    @Override{{if deprecated .}}
+    @Deprecated{{end}}
     {{methodSigWithHeader .}} {
         String xPath = "{{.Path}}";
         String xBody = null;
@@ -467,5 +480,5 @@ func (gen *javaClientGenerator) clientMethodOverloadContent(r *rdl.Resource) str
 func (gen *javaClientGenerator) javaType(reg rdl.TypeRegistry, rdlType rdl.TypeRef, optional bool, items rdl.TypeRef, keys rdl.TypeRef) string {
 	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
 	checkErr(err)
-	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver)
+	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver, gen.genValueTypes)
 }
@@ -26,12 +26,17 @@ const (
 	JavaxConstraintPackage        = "javax.validation.constraints"
 	JavaxValidationPackage        = "javax.validation"
 	JavaxXmlBindAnnotationPackage = "javax.xml.bind.annotation"
+	JacksonAnnotationPackage      = "com.fasterxml.jackson.annotation"
+	JacksonCorePackage            = "com.fasterxml.jackson.core"
+	JacksonDatabindPackage        = "com.fasterxml.jackson.databind"
+	CommonsLangBuilderPackage     = "org.apache.commons.lang3.builder"
 	HibernateConstraintPackage    = "org.hibernate.validator.constraints"
 	ParsecConstraintPackage       = "com.yahoo.parsec.constraint.validators"
 	ValidationGroupsKey           = "groups"
 	ValidationGroupsClass         = "ParsecValidationGroups"
 	ValidationGroupsRegexPattern  = "(^|[ ,])" + ValidationGroupsKey + "\\s?="
 	JavaClassSuffix               = utils.JavaParsecClassSuffix
+	DiscriminatorAnnotationKey    = "x_discriminator"
 )
 
 const (
@@ -39,6 +44,12 @@ const (
 	JavaBeanNamingStyle   = "java_bean"
 )
 
+const (
+	MutableStyle   = "mutable"
+	ImmutableStyle = "immutable"
+	RecordStyle    = "record"
+)
+
 var (
 	validationGroupsRegex = regexp.MustCompile(ValidationGroupsRegexPattern)
 )
@@ -62,6 +73,9 @@ type javaModelGenerator struct {
 	body       []string
 	isPcSuffix bool
 	namingStyle string
+	genValueTypes bool
+	style string
+	jakarta bool
 }
 
 func main() {
@@ -72,12 +86,24 @@ func main() {
 	dataFile := flag.String("df", "", "JSON representation of the schema file")
 	pc := flag.String("pc", "false", "add '_Pc' postfix to the generated java class")
     namgingStyle := flag.String("namingStyle", UpperFirstNamingStyle, "getter/setter use java bean naming convection")
+	vt := flag.String("vt", "false", "generate value types for the named array, map, string and number types")
+	style := flag.String("style", MutableStyle, "struct generation style: mutable (setters), immutable (builder) or record (java 17)")
+	jakartaString := flag.String("jakarta", "false", utils.JakartaFlagUsage)
 	flag.Parse()
 
 	generateAnnotations, err := strconv.ParseBool(*generateAnnotationsString)
 	checkErr(err)
 	isPcSuffix, err := strconv.ParseBool(*pc)
 	checkErr(err)
+	genValueTypes, err := strconv.ParseBool(*vt)
+	checkErr(err)
+	jakarta, err := strconv.ParseBool(*jakartaString)
+	checkErr(err)
+	switch *style {
+	case MutableStyle, ImmutableStyle, RecordStyle:
+	default:
+		checkErr(fmt.Errorf("Unsupported style: %s", *style))
+	}
 
 	var data []byte
 	if *dataFile != "" {
@@ -94,7 +120,7 @@ func main() {
 		var schema rdl.Schema
 		err = json.Unmarshal(data, &schema)
 		if err == nil {
-			GenerateJavaModel(banner, &schema, *pOutdir, generateAnnotations, *namespace, isPcSuffix, *namgingStyle)
+			GenerateJavaModel(banner, &schema, *pOutdir, generateAnnotations, *namespace, isPcSuffix, *namgingStyle, genValueTypes, *style, jakarta)
 			os.Exit(0)
 		}
 	}
@@ -110,7 +136,7 @@ func checkErr(err error) {
 }
 
 // GenerateJavaModel generates the model code for the types defined in the RDL schema.
-func GenerateJavaModel(banner string, schema *rdl.Schema, outdir string, genAnnotations bool, namespace string, isPcSuffix bool, namingStyle string) error {
+func GenerateJavaModel(banner string, schema *rdl.Schema, outdir string, genAnnotations bool, namespace string, isPcSuffix bool, namingStyle string, genValueTypes bool, style string, jakarta bool) error {
 	packageDir, err := utils.JavaGenerationDir(outdir, schema, namespace)
 	if err != nil {
 		return err
@@ -118,7 +144,7 @@ func GenerateJavaModel(banner string, schema *rdl.Schema, outdir string, genAnno
 	validationGroups = make(map[string]struct{}, 0)
 	registry := rdl.NewTypeRegistry(schema)
 	for _, t := range schema.Types {
-		err := generateJavaType(banner, schema, registry, packageDir, t, genAnnotations, namespace, isPcSuffix, namingStyle)
+		err := generateJavaType(banner, schema, registry, packageDir, t, genAnnotations, namespace, isPcSuffix, namingStyle, genValueTypes, style, jakarta)
 		if err != nil {
 			return err
 		}
@@ -128,18 +154,19 @@ func GenerateJavaModel(banner string, schema *rdl.Schema, outdir string, genAnno
 }
 
 func generateJavaType(banner string, schema *rdl.Schema, registry rdl.TypeRegistry, outdir string, t *rdl.Type,
-	genAnnotations bool, namespace string, isPcSuffix bool, namingStyle string) error {
+	genAnnotations bool, namespace string, isPcSuffix bool, namingStyle string, genValueTypes bool, style string, jakarta bool) error {
 
 	tName, _, _ := rdl.TypeInfo(t)
 	bt := registry.BaseType(t)
 	switch bt {
 	case rdl.BaseTypeStruct:
-	//case rdl.BaseTypeUnion:
-	//case rdl.BaseTypeArray: //? a list subtype, to avoid generics and erasure?
+	case rdl.BaseTypeUnion:
 	case rdl.BaseTypeEnum:
 	default:
-		fmt.Fprintf(os.Stderr, "[Ignoring type %s]\n", tName)
-		return nil
+		if !genValueTypes || !utils.IsJavaValueType(registry, t) {
+			fmt.Fprintf(os.Stderr, "[Ignoring type %s]\n", tName)
+			return nil
+		}
 	}
 	cName := utils.Capitalize(string(tName))
 	ver, err := utils.GetSchemaVersionOrDefault(schema, 1)
@@ -157,7 +184,7 @@ func generateJavaType(banner string, schema *rdl.Schema, registry rdl.TypeRegist
 	if file != nil {
 		defer file.Close()
 	}
-	gen := &javaModelGenerator{registry, schema, string(tName), out, nil, nil, nil, nil, isPcSuffix, namingStyle}
+	gen := &javaModelGenerator{registry, schema, string(tName), out, nil, nil, nil, nil, isPcSuffix, namingStyle, genValueTypes, style, jakarta}
 	gen.generateHeader(banner, namespace)
 	switch bt {
 	case rdl.BaseTypeStruct:
@@ -165,14 +192,14 @@ func generateJavaType(banner string, schema *rdl.Schema, registry rdl.TypeRegist
 		gen.generateStruct(t, cName, genAnnotations)
 	case rdl.BaseTypeUnion:
 		gen.appendToBody("\n")
-		gen.generateUnion(t)
-	case rdl.BaseTypeArray:
-		gen.appendToBody("\n")
-		gen.generateArray(t)
+		gen.generateUnion(t, cName)
 	case rdl.BaseTypeEnum:
 		gen.appendToBody("\n")
 		gen.generateTypeComment(t)
 		gen.generateEnum(t)
+	default:
+		gen.appendToBody("\n")
+		gen.generateValueType(t, cName, genAnnotations)
 	}
 
 	for _, header := range gen.header {
@@ -234,13 +261,6 @@ func (gen *javaModelGenerator) generateHeader(banner string, namespace string) {
 	}
 	gen.imports = append(gen.imports, "import java.util.List;\n")
 	gen.imports = append(gen.imports, "import java.util.Map;\n")
-
-	// import hashcode, equals builders, toString builder
-	gen.imports = append(gen.imports, "import org.apache.commons.lang3.builder.EqualsBuilder;\n")
-	gen.imports = append(gen.imports, "import org.apache.commons.lang3.builder.HashCodeBuilder;\n")
-	gen.imports = append(gen.imports, "import org.apache.commons.lang3.builder.ToStringBuilder;\n")
-	gen.imports = append(gen.imports, "import org.apache.commons.lang3.builder.ToStringStyle;\n")
-	gen.imports = append(gen.imports, "import javax.xml.bind.annotation.XmlAnyElement;\n")
 }
 
 func (gen *javaModelGenerator) generateTypeComment(t *rdl.Type) {
@@ -250,9 +270,11 @@ func (gen *javaModelGenerator) generateTypeComment(t *rdl.Type) {
 		s += " " + tComment
 	}
 	gen.appendToBody(utils.FormatComment(s, 0, 80))
+	gen.appendToBody(utils.JavaDeprecation(utils.TypeAnnotations(t), 0))
 }
 
 func (gen *javaModelGenerator) generateEquals() {
+	gen.appendImportClass(CommonsLangBuilderPackage + ".EqualsBuilder")
 	gen.appendToBody("\n")
 	gen.appendToBody("    @Override\n")
 	gen.appendToBody("    public boolean equals(Object obj) {\n")
@@ -261,6 +283,7 @@ func (gen *javaModelGenerator) generateEquals() {
 }
 
 func (gen *javaModelGenerator) generateHashCode() {
+	gen.appendImportClass(CommonsLangBuilderPackage + ".HashCodeBuilder")
 	gen.appendToBody("\n")
 	gen.appendToBody("    @Override\n")
 	gen.appendToBody("    public int hashCode() {\n")
@@ -269,6 +292,8 @@ func (gen *javaModelGenerator) generateHashCode() {
 }
 
 func (gen *javaModelGenerator) generateToString() {
+	gen.appendImportClass(CommonsLangBuilderPackage + ".ToStringBuilder")
+	gen.appendImportClass(CommonsLangBuilderPackage + ".ToStringStyle")
 	gen.appendToBody("\n")
 	gen.appendToBody("    @Override\n")
 	gen.appendToBody("    public String toString() {\n")
@@ -276,246 +301,188 @@ func (gen *javaModelGenerator) generateToString() {
 	gen.appendToBody("    }\n")
 }
 
-func (gen *javaModelGenerator) generateUnion(t *rdl.Type) {
-	tName, _, _ := rdl.TypeInfo(t)
-	ut := t.UnionTypeDef
-	uName := utils.Capitalize(string(tName))
-	gen.appendToBody(fmt.Sprintf("// %sVariantTag - generated to support %s\n", uName, uName))
-	gen.appendToBody(fmt.Sprintf("type %sVariantTag int\n\n", uName))
-	gen.appendToBody("// Supporting constants\n")
-	gen.appendToBody("const (\n")
-	gen.appendToBody(fmt.Sprintf("\t_ %sVariantTag = iota\n", uName))
-	for _, v := range ut.Variants {
-		uV := utils.Capitalize(string(v))
-		gen.appendToBody(fmt.Sprintf("\t%sVariant%s\n", uName, uV))
+func (gen *javaModelGenerator) generateUnion(t *rdl.Type, cName string) {
+	if gen.err != nil {
+		return
 	}
-	gen.appendToBody(")\n\n")
+	ut := t.UnionTypeDef
+	gen.appendImportClass("java.io.IOException")
+	gen.appendImportClass(JacksonCorePackage + ".JsonGenerator")
+	gen.appendImportClass(JacksonCorePackage + ".JsonParser")
+	gen.appendImportClass(JacksonCorePackage + ".ObjectCodec")
+	gen.appendImportClass(JacksonDatabindPackage + ".DeserializationContext")
+	gen.appendImportClass(JacksonDatabindPackage + ".JsonMappingException")
+	gen.appendImportClass(JacksonDatabindPackage + ".JsonNode")
+	gen.appendImportClass(JacksonDatabindPackage + ".SerializerProvider")
+	gen.appendImportClass(JacksonDatabindPackage + ".annotation.JsonDeserialize")
+	gen.appendImportClass(JacksonDatabindPackage + ".annotation.JsonSerialize")
+	gen.appendImportClass(JacksonDatabindPackage + ".deser.std.StdDeserializer")
+	gen.appendImportClass(JacksonDatabindPackage + ".ser.std.StdSerializer")
 
-	maxKeyLen := len("Variant")
-	for _, v := range ut.Variants {
-		if len(v) > maxKeyLen {
-			maxKeyLen = len(v)
+	gen.generateTypeComment(t)
+	gen.appendToBody(fmt.Sprintf("@JsonSerialize(using = %s.Serializer.class)\n", cName))
+	gen.appendToBody(fmt.Sprintf("@JsonDeserialize(using = %s.Deserializer.class)\n", cName))
+	gen.appendToBody(fmt.Sprintf("public final class %s implements java.io.Serializable {\n", cName))
+
+	// the variant tag
+	gen.appendToBody("\n    public enum Variant {")
+	for i, v := range ut.Variants {
+		if i > 0 {
+			gen.appendToBody(",")
 		}
+		gen.appendToBody(fmt.Sprintf("\n        %s", utils.Capitalize(string(v))))
 	}
-	gen.generateTypeComment(t)
-	gen.appendToBody(fmt.Sprintf("type %s struct {\n", uName))
-	s := utils.LeftJustified("Variant", maxKeyLen)
-	gen.appendToBody(fmt.Sprintf("\t%s %sVariantTag\n", s, uName))
+	gen.appendToBody("\n    }\n\n")
+	gen.appendToBody("    private final Variant variant;\n")
+	gen.appendToBody("    private final Object value;\n\n")
+	gen.appendToBody(fmt.Sprintf("    private %s(Variant variant, Object value) {\n", cName))
+	gen.appendToBody("        this.variant = variant;\n")
+	gen.appendToBody("        this.value = value;\n")
+	gen.appendToBody("    }\n")
+
+	// factories and typed accessors, one per variant
 	for _, v := range ut.Variants {
 		uV := utils.Capitalize(string(v))
-		vType := gen.javaType(gen.registry, v, false, "", "")
-		s := utils.LeftJustified(uV, maxKeyLen)
-		gen.appendToBody(fmt.Sprintf("\t%s *%s\n", s, vType))
+		vType := gen.javaType(gen.registry, v, true, "", "")
+		gen.appendToBody(fmt.Sprintf("\n    public static %s of%s(%s value) {\n", cName, uV, vType))
+		gen.appendToBody(fmt.Sprintf("        return new %s(Variant.%s, value);\n", cName, uV))
+		gen.appendToBody("    }\n")
 	}
-	gen.appendToBody("}\n\n")
-	gen.appendToBody(fmt.Sprintf("func (u %s) String() string {\n", uName))
-	gen.appendToBody("\tswitch u.Variant {\n")
+	gen.appendToBody("\n    public Variant getVariant() { return variant; }\n")
 	for _, v := range ut.Variants {
 		uV := utils.Capitalize(string(v))
-		gen.appendToBody(fmt.Sprintf("\tcase %sVariant%s:\n", uName, uV))
-		gen.appendToBody(fmt.Sprintf("\t\treturn fmt.Sprintf(\"%%v\", u.%s)\n", uV))
-	}
-	gen.appendToBody("\tdefault:\n")
-	gen.appendToBody(fmt.Sprintf("\t\treturn \"<%s uninitialized>\"\n", uName))
-	gen.appendToBody("\t}\n")
-	gen.appendToBody("}\n\n")
-	gen.appendToBody(fmt.Sprintf("// MarshalJSON for %s\n", uName))
-	gen.appendToBody(fmt.Sprintf("func (u %s) MarshalJSON() ([]byte, error) {\n", uName))
-	gen.appendToBody("\tswitch u.Variant {\n")
-	for _, v := range ut.Variants {
-		uV := utils.Capitalize(string(v))
-		gen.appendToBody(fmt.Sprintf("\tcase %sVariant%s:\n", uName, uV))
-		gen.appendToBody(fmt.Sprintf("\t\treturn json.Marshal(u.%s)\n", uV))
+		vType := gen.javaType(gen.registry, v, true, "", "")
+		gen.appendToBody(fmt.Sprintf("\n    public %s get%s() { return variant == Variant.%s ? (%s) value : null; }\n", vType, uV, uV, vType))
 	}
-	gen.appendToBody("\tdefault:\n")
-	gen.appendToBody(fmt.Sprintf("\t\treturn nil, fmt.Errorf(\"Cannot marshal uninitialized union type %s\")\n", uName))
-	gen.appendToBody("\t}\n")
-	gen.appendToBody("}\n\n")
 
-	hasStructs := false
-	for _, v := range ut.Variants {
-		t := gen.registry.FindType(v)
-		if t != nil {
-			bt := gen.registry.BaseType(t)
-			if bt == rdl.BaseTypeStruct {
-				if t.StructTypeDef != nil && t.StructTypeDef.Fields != nil {
-					hasStructs = true
-					break
-				}
-			}
-		}
-	}
-	if hasStructs {
-		gen.appendToBody(fmt.Sprintf("func check%sStructFields(repr map[string]interface{}, fields ...string) bool {\n", uName))
-		gen.appendToBody("\tfor _, s := range fields {\n")
-		gen.appendToBody("\t\tif _, ok := repr[s]; !ok {\n")
-		gen.appendToBody("\t\t\treturn false\n")
-		gen.appendToBody("\t\t}\n")
-		gen.appendToBody("\t}\n")
-		gen.appendToBody("\treturn true\n")
-		gen.appendToBody("}\n\n")
-	}
+	gen.generateHashCode()
+	gen.generateEquals()
+	gen.generateToString()
 
-	var structVariants []rdl.TypeRef
-	var stringVariants []rdl.TypeRef
-	var boolVariants []rdl.TypeRef
-	var int8Variants []rdl.TypeRef
-	var int16Variants []rdl.TypeRef
-	var int32Variants []rdl.TypeRef
-	var int64Variants []rdl.TypeRef
-	var float32Variants []rdl.TypeRef
-	var float64Variants []rdl.TypeRef
+	// serializer: write whichever variant is set
+	gen.appendToBody(fmt.Sprintf("\n    public static class Serializer extends StdSerializer<%s> {\n", cName))
+	gen.appendToBody(fmt.Sprintf("        public Serializer() { super(%s.class); }\n\n", cName))
+	gen.appendToBody("        @Override\n")
+	gen.appendToBody(fmt.Sprintf("        public void serialize(%s union, JsonGenerator generator, SerializerProvider provider) throws IOException {\n", cName))
+	gen.appendToBody("            provider.defaultSerializeValue(union.value, generator);\n")
+	gen.appendToBody("        }\n")
+	gen.appendToBody("    }\n")
 
+	gen.generateUnionDeserializer(ut, cName)
+	gen.appendToBody("}\n")
+}
+
+// generateUnionDeserializer picks the variant from the shape of the JSON value: objects match the first struct
+// variant whose required fields are all present, strings are tried against the enum variants before falling back
+// to the first string variant, and other values match the first variant of the corresponding type.
+func (gen *javaModelGenerator) generateUnionDeserializer(ut *rdl.UnionTypeDef, cName string) {
+	var objectChecks, objectDefaults, arrays, enums, texts, bools, integers, numbers []string
+	// with a discriminator, the struct variant is picked by the value of that property instead of by its fields
+	discriminator := ut.Annotations[DiscriminatorAnnotationKey]
+	var cases []string
 	for _, v := range ut.Variants {
 		uV := utils.Capitalize(string(v))
-		t := gen.registry.FindType(v)
-		switch t.Variant {
-		case rdl.TypeVariantStructTypeDef:
-			structVariants = append(structVariants, v)
-			if t.StructTypeDef.Fields != nil {
-				names := ""
-				for _, f := range t.StructTypeDef.Fields {
-					s := fmt.Sprintf("%q", f.Name)
-					if names == "" {
-						names = s
-					} else {
-						names = names + ", " + s
-					}
-				}
-				if names != "" {
-
-					gen.appendToBody(fmt.Sprintf("func make%sVariant%s(b []byte, u *%s, fields map[string]interface{}) bool {\n", uName, uV, uName))
-					gen.appendToBody(fmt.Sprintf("\tif check%sStructFields(fields, %s) {\n", uName, names))
-					gen.appendToBody(fmt.Sprintf("\t\tvar o %s\n", uV))
-					gen.appendToBody("\t\tif err := json.Unmarshal(b, &o); err == nil {\n")
-					gen.appendToBody(fmt.Sprintf("\t\t\tup := new(%s)\n", uName))
-					gen.appendToBody(fmt.Sprintf("\t\t\tup.Variant = %sVariant%s\n", uName, uV))
-					gen.appendToBody(fmt.Sprintf("\t\t\tup.%s = &o\n", uV))
-					gen.appendToBody("\t\t\t*u = *up\n")
-					gen.appendToBody("\t\t\treturn true\n")
-					gen.appendToBody("\t\t}\n")
-					gen.appendToBody("\t}\n")
-					gen.appendToBody("\treturn false\n")
-					gen.appendToBody("}\n\n")
+		vType := gen.javaType(gen.registry, v, true, "", "")
+		switch gen.registry.FindBaseType(v) {
+		case rdl.BaseTypeStruct:
+			var names []string
+			for _, f := range utils.FlattenedFields(gen.registry, gen.registry.FindType(v)) {
+				if !f.Optional {
+					names = append(names, fmt.Sprintf("%q", f.Name))
 				}
 			}
-		default:
-			bt := gen.registry.FindBaseType(v)
-			switch bt {
-			case rdl.BaseTypeBool:
-				boolVariants = append(boolVariants, v)
-			case rdl.BaseTypeString:
-				stringVariants = append(stringVariants, v)
-			case rdl.BaseTypeInt8:
-				int8Variants = append(int8Variants, v)
-			case rdl.BaseTypeInt16:
-				int16Variants = append(int16Variants, v)
-			case rdl.BaseTypeInt32:
-				int32Variants = append(int32Variants, v)
-			case rdl.BaseTypeInt64:
-				int64Variants = append(int64Variants, v)
-			case rdl.BaseTypeFloat32:
-				float32Variants = append(float32Variants, v)
-			case rdl.BaseTypeFloat64:
-				float64Variants = append(float64Variants, v)
-			case rdl.BaseTypeEnum:
-			default:
-				panic("fix me: " + bt.String())
+			read := fmt.Sprintf("return of%s(codec.treeToValue(node, %s.class));", uV, vType)
+			if discriminator != "" {
+				cases = append(cases, fmt.Sprintf("    case %q:\n                        %s", string(v), read))
+			} else if len(names) > 0 {
+				objectChecks = append(objectChecks, fmt.Sprintf("if (hasFields(node, %s)) {\n                    %s\n                }", strings.Join(names, ", "), read))
+			} else {
+				objectDefaults = append(objectDefaults, read)
 			}
+		case rdl.BaseTypeMap:
+			gen.appendImportClass(JacksonCorePackage + ".type.TypeReference")
+			objectDefaults = append(objectDefaults, fmt.Sprintf("return of%s(codec.readValue(codec.treeAsTokens(node), new TypeReference<%s>() { }));", uV, vType))
+		case rdl.BaseTypeArray:
+			gen.appendImportClass(JacksonCorePackage + ".type.TypeReference")
+			arrays = append(arrays, fmt.Sprintf("return of%s(codec.readValue(codec.treeAsTokens(node), new TypeReference<%s>() { }));", uV, vType))
+		case rdl.BaseTypeEnum:
+			enums = append(enums, fmt.Sprintf("try {\n                    return of%s(%s.fromString(node.asText()));\n                } catch (IllegalArgumentException e) {\n                    // not this variant\n                }", uV, vType))
+		case rdl.BaseTypeString, rdl.BaseTypeSymbol, rdl.BaseTypeUUID, rdl.BaseTypeTimestamp:
+			texts = append(texts, gen.unionRead(v, uV, vType, "node.asText()"))
+		case rdl.BaseTypeBool:
+			bools = append(bools, gen.unionRead(v, uV, vType, "node.asBoolean()"))
+		case rdl.BaseTypeInt8:
+			integers = append(integers, gen.unionRead(v, uV, vType, "(byte) node.asInt()"))
+		case rdl.BaseTypeInt16:
+			integers = append(integers, gen.unionRead(v, uV, vType, "(short) node.asInt()"))
+		case rdl.BaseTypeInt32:
+			integers = append(integers, gen.unionRead(v, uV, vType, "node.asInt()"))
+		case rdl.BaseTypeInt64:
+			integers = append(integers, gen.unionRead(v, uV, vType, "node.asLong()"))
+		case rdl.BaseTypeFloat32:
+			numbers = append(numbers, gen.unionRead(v, uV, vType, "(float) node.asDouble()"))
+		case rdl.BaseTypeFloat64:
+			numbers = append(numbers, gen.unionRead(v, uV, vType, "node.asDouble()"))
+		default:
+			fmt.Fprintf(os.Stderr, "[Ignoring variant %s of union %s]\n", v, ut.Name)
 		}
 	}
-	gen.appendToBody(fmt.Sprintf("// UnmarshalJSON for %s\n", uName))
-	gen.appendToBody(fmt.Sprintf("func (u *%s) UnmarshalJSON(b []byte) error {\n", uName))
-	gen.appendToBody("\tvar tmp interface{}\n")
-	gen.appendToBody("\tif err := json.Unmarshal(b, &tmp); err != nil {\n")
-	gen.appendToBody("\t\treturn err\n")
-	gen.appendToBody("\t}\n")
-	gen.appendToBody("\tswitch v := tmp.(type) {\n")
-
-	if len(structVariants) > 0 {
-		gen.appendToBody("\tcase map[string]interface{}:\n")
-		for _, v := range structVariants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\tif make%sVariant%s(b, u, v) {\n", uName, uV))
-			gen.appendToBody("\t\t\treturn nil\n")
-			gen.appendToBody("\t\t}\n")
-		}
-	}
-	if len(stringVariants) > 0 {
-		gen.appendToBody("\tcase string:\n")
-		for _, v := range stringVariants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, &v, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(boolVariants) > 0 {
-		gen.appendToBody("\tcase bool:\n")
-		for _, v := range boolVariants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, &v, nil, nil, nil, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(int8Variants) > 0 {
-		gen.appendToBody("\tcase int8:\n")
-		for _, v := range int8Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, &v, nil, nil, nil, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(int16Variants) > 0 {
-		gen.appendToBody("\tcase int16:\n")
-		for _, v := range int16Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, nil, &v, nil, nil, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(int32Variants) > 0 {
-		gen.appendToBody("\tcase int32:\n")
-		for _, v := range int32Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, nil, nil, &v, nil, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(int64Variants) > 0 {
-		gen.appendToBody("\tcase int64:\n")
-		for _, v := range int64Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, nil, nil, nil, &v, nil, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(float32Variants) > 0 {
-		gen.appendToBody("\tcase float32:\n")
-		for _, v := range float32Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, nil, nil, nil, nil, &v, nil}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	if len(float64Variants) > 0 {
-		gen.appendToBody("\tcase float64:\n")
-		for _, v := range float64Variants {
-			uV := utils.Capitalize(string(v))
-			gen.appendToBody(fmt.Sprintf("\t\t*u = %s{%sVariant%s, nil, nil, nil, nil, nil, &v}\n", uName, uName, uV))
-			gen.appendToBody("\t\treturn nil\n")
-			break //the first one is the one we take
-		}
-	}
-	gen.appendToBody("\t}\n")
-	gen.appendToBody(fmt.Sprintf("\treturn fmt.Errorf(\"Cannot unmarshal JSON to union type %s\")\n", uName))
-	gen.appendToBody("}\n")
+
+	gen.appendToBody(fmt.Sprintf("\n    public static class Deserializer extends StdDeserializer<%s> {\n", cName))
+	gen.appendToBody(fmt.Sprintf("        public Deserializer() { super(%s.class); }\n\n", cName))
+	gen.appendToBody("        @Override\n")
+	gen.appendToBody(fmt.Sprintf("        public %s deserialize(JsonParser parser, DeserializationContext context) throws IOException {\n", cName))
+	gen.appendToBody("            ObjectCodec codec = parser.getCodec();\n")
+	gen.appendToBody("            JsonNode node = codec.readTree(parser);\n")
+	if len(cases) > 0 {
+		objectChecks = append(objectChecks, fmt.Sprintf("switch (node.path(%q).asText()) {\n                %s\n                    default:\n                        break;\n                }", discriminator, strings.Join(cases, "\n                ")))
+	}
+	gen.appendUnionBranch("node.isObject()", append(objectChecks, firstOf(objectDefaults)...))
+	gen.appendUnionBranch("node.isArray()", firstOf(arrays))
+	gen.appendUnionBranch("node.isTextual()", append(enums, firstOf(texts)...))
+	gen.appendUnionBranch("node.isBoolean()", firstOf(bools))
+	gen.appendUnionBranch("node.isIntegralNumber()", firstOf(integers))
+	gen.appendUnionBranch("node.isNumber()", firstOf(numbers))
+	gen.appendToBody(fmt.Sprintf("            throw JsonMappingException.from(parser, \"Cannot deserialize JSON to union type %s\");\n", cName))
+	gen.appendToBody("        }\n")
+	if len(objectChecks) > 0 && discriminator == "" {
+		gen.appendToBody("\n        private static boolean hasFields(JsonNode node, String... fields) {\n")
+		gen.appendToBody("            for (String field : fields) {\n")
+		gen.appendToBody("                if (!node.has(field)) {\n")
+		gen.appendToBody("                    return false;\n")
+		gen.appendToBody("                }\n")
+		gen.appendToBody("            }\n")
+		gen.appendToBody("            return true;\n")
+		gen.appendToBody("        }\n")
+	}
+	gen.appendToBody("    }\n")
+}
+
+// unionRead reads a scalar variant from the node, through Jackson when the variant is a value type
+func (gen *javaModelGenerator) unionRead(v rdl.TypeRef, uV string, vType string, expr string) string {
+	if gen.isValueType(v) {
+		return fmt.Sprintf("return of%s(codec.treeToValue(node, %s.class));", uV, vType)
+	}
+	return fmt.Sprintf("return of%s(%s);", uV, expr)
+}
+
+func (gen *javaModelGenerator) appendUnionBranch(cond string, stmts []string) {
+	if len(stmts) == 0 {
+		return
+	}
+	gen.appendToBody(fmt.Sprintf("            if (%s) {\n", cond))
+	for _, stmt := range stmts {
+		gen.appendToBody(fmt.Sprintf("                %s\n", stmt))
+	}
+	gen.appendToBody("            }\n")
+}
+
+// firstOf keeps only the first candidate: like the Go union logic, the first variant of a kind is the one we take
+func firstOf(stmts []string) []string {
+	if len(stmts) > 1 {
+		return stmts[:1]
+	}
+	return stmts
 }
 
 func (gen *javaModelGenerator) literal(lit interface{}) string {
@@ -539,21 +506,104 @@ func (gen *javaModelGenerator) literal(lit interface{}) string {
 	}
 }
 
-func (gen *javaModelGenerator) generateArray(t *rdl.Type) {
-	if gen.err == nil {
-		switch t.Variant {
-		case rdl.TypeVariantArrayTypeDef:
-			at := t.ArrayTypeDef
-			gen.generateTypeComment(t)
-			ftype := gen.javaType(gen.registry, at.Type, false, at.Items, "")
-			gen.appendToBody(fmt.Sprintf("type %s %s\n\n", at.Name, ftype))
-		default:
-			tName, tType, _ := rdl.TypeInfo(t)
-			gtype := gen.javaType(gen.registry, tType, false, "", "")
-			gen.generateTypeComment(t)
-			gen.appendToBody(fmt.Sprintf("type %s %s\n\n", tName, gtype))
+// generateValueType wraps the List, Map, String or number behind a named type, so that the name and the constraints
+// of the typedef carry over to Java. The wrapper is (de)serialized as the bare value.
+func (gen *javaModelGenerator) generateValueType(t *rdl.Type, cName string, genAnnotations bool) {
+	if gen.err != nil {
+		return
+	}
+	gen.appendImportClass(JacksonAnnotationPackage + ".JsonCreator")
+	gen.appendImportClass(JacksonAnnotationPackage + ".JsonValue")
+
+	gen.generateTypeComment(t)
+	gen.appendToBody(fmt.Sprintf("public final class %s implements java.io.Serializable {\n\n", cName))
+	if genAnnotations {
+		annotations := gen.valueTypeAnnotations(t)
+		keys := make([]string, 0, len(annotations))
+		for k := range annotations {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			gen.appendToBody("    ")
+			gen.generateValidationGroupAnnotation(rdl.ExtendedAnnotation(k), annotations[rdl.ExtendedAnnotation(k)])
+			gen.appendToBody("\n")
+		}
+	}
+	gen.appendToBody("    private final ")
+	vType := gen.generateValueTypeField(t)
+	gen.appendToBody(" value;\n")
+
+	gen.appendToBody("\n    @JsonCreator(mode = JsonCreator.Mode.DELEGATING)\n")
+	gen.appendToBody(fmt.Sprintf("    public %s(%s value) {\n", cName, vType))
+	gen.appendToBody("        this.value = value;\n")
+	gen.appendToBody("    }\n")
+	gen.appendToBody("\n    @JsonValue\n")
+	gen.appendToBody(fmt.Sprintf("    public %s getValue() { return value; }\n", vType))
+
+	gen.generateHashCode()
+	gen.generateEquals()
+	gen.appendToBody("\n")
+	gen.appendToBody("    @Override\n")
+	gen.appendToBody("    public String toString() {\n")
+	gen.appendToBody("        return String.valueOf(value);\n")
+	gen.appendToBody("    }\n")
+	gen.appendToBody("}\n")
+}
+
+// generateValueTypeField appends the type of the wrapped value, with the annotations of its items if any, and returns
+// the plain java type for the constructor and the getter.
+func (gen *javaModelGenerator) generateValueTypeField(t *rdl.Type) string {
+	switch t.Variant {
+	case rdl.TypeVariantArrayTypeDef:
+		at := t.ArrayTypeDef
+		gen.appendToBody("List<")
+		gen.generateStructFieldParamType(at.Items, true, "", "")
+		gen.appendToBody(">")
+		return "List<" + gen.javaType(gen.registry, at.Items, true, "", "") + ">"
+	case rdl.TypeVariantMapTypeDef:
+		mt := t.MapTypeDef
+		gen.appendToBody("Map<")
+		gen.generateStructFieldParamType(mt.Keys, true, "", "")
+		gen.appendToBody(", ")
+		gen.generateStructFieldParamType(mt.Items, true, "", "")
+		gen.appendToBody(">")
+		return "Map<" + gen.javaType(gen.registry, mt.Keys, true, "", "") + "," + gen.javaType(gen.registry, mt.Items, true, "", "") + ">"
+	default:
+		vType := gen.javaType(gen.registry, rdl.TypeRef(gen.registry.BaseType(t).String()), false, "", "")
+		gen.appendToBody(vType)
+		return vType
+	}
+}
+
+// valueTypeAnnotations returns the constraints of the typedef, the explicit x_ annotations taking precedence
+func (gen *javaModelGenerator) valueTypeAnnotations(t *rdl.Type) map[rdl.ExtendedAnnotation]string {
+	tName, _, _ := rdl.TypeInfo(t)
+	return utils.ConstraintAnnotations(gen.registry, rdl.TypeRef(tName), gen.schema.Types, nil)
+}
+
+// constraintAnnotations returns the explicit annotations of a value of the given type, along with the constraints it
+// inherits from the type. A value type already holds those on its wrapped value, so they are not repeated.
+func (gen *javaModelGenerator) constraintAnnotations(rdlType rdl.TypeRef, explicit map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	if gen.isValueType(rdlType) {
+		if explicit == nil {
+			return make(map[rdl.ExtendedAnnotation]string)
 		}
+		return explicit
+	}
+	return utils.ConstraintAnnotations(gen.registry, rdlType, gen.schema.Types, explicit)
+}
+
+// inlineDeprecation adds the deprecation of the field's type to the field, unless the type has a class of its own.
+func (gen *javaModelGenerator) inlineDeprecation(rdlType rdl.TypeRef, annotations map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	if gen.isValueType(rdlType) {
+		return annotations
 	}
+	return utils.InlineDeprecation(gen.registry, rdlType, annotations)
+}
+
+func (gen *javaModelGenerator) isValueType(rdlType rdl.TypeRef) bool {
+	return gen.genValueTypes && utils.IsJavaValueType(gen.registry, gen.registry.FindType(rdlType))
 }
 
 func (gen *javaModelGenerator) generateStruct(t *rdl.Type, cName string, genAnnotations bool) {
@@ -562,18 +612,19 @@ func (gen *javaModelGenerator) generateStruct(t *rdl.Type, cName string, genAnno
 		case rdl.TypeVariantStructTypeDef:
 			st := t.StructTypeDef
 			f := utils.FlattenedFields(gen.registry, t)
+			if gen.style == RecordStyle {
+				gen.generateRecord(t, f, cName, genAnnotations)
+				return
+			}
 			gen.generateTypeComment(t)
+			if gen.style == ImmutableStyle {
+				gen.generateImmutableStruct(st, f, cName, genAnnotations)
+				return
+			}
 			gen.appendToBody(fmt.Sprintf("public final class %s implements java.io.Serializable {\n", cName))
 			gen.generateStructFields(f, st.Name, st.Comment, cName, st.Annotations, genAnnotations)
 			if gen.structHasFieldDefault(st) {
-				gen.appendToBody("\n    //\n    // sets up the instance according to its default field values, if any\n    //\n")
-				gen.appendToBody(fmt.Sprintf("    private void init() {\n"))
-				for _, f := range f {
-					if f.Default != nil {
-						gen.appendToBody(fmt.Sprintf("        %s = %s;\n", f.Name, gen.literal(f.Default)))
-					}
-				}
-				gen.appendToBody("    }\n")
+				gen.generateInit(f, "", "instance")
 				gen.appendToBody(fmt.Sprintf("    public %s() { init(); }\n", cName))
 			} else {
 				gen.appendToBody(fmt.Sprintf("    public %s() {  }\n", cName))
@@ -589,6 +640,273 @@ func (gen *javaModelGenerator) generateStruct(t *rdl.Type, cName string, genAnno
 	}
 }
 
+// generateInit writes the init() method setting the fields to their default values, indented one more level for
+// the nested classes.
+func (gen *javaModelGenerator) generateInit(fields []*rdl.StructFieldDef, indent string, what string) {
+	gen.appendToBody(fmt.Sprintf("\n%s    //\n%s    // sets up the %s according to its default field values, if any\n%s    //\n", indent, indent, what, indent))
+	gen.appendToBody(fmt.Sprintf("%s    private void init() {\n", indent))
+	for _, f := range fields {
+		if f.Default != nil {
+			gen.appendToBody(fmt.Sprintf("%s        %s = %s;\n", indent, f.Name, gen.defaultValue(f, false)))
+		}
+	}
+	gen.appendToBody(fmt.Sprintf("%s    }\n", indent))
+}
+
+// defaultValue returns the java expression of the default value of the field. A boxed field needs the literal to be
+// of its exact type, since only int constants get boxed to a Long or a Double otherwise.
+func (gen *javaModelGenerator) defaultValue(f *rdl.StructFieldDef, boxed bool) string {
+	if gen.isValueType(f.Type) {
+		return fmt.Sprintf("new %s(%s)", gen.javaType(gen.registry, f.Type, false, "", ""), gen.literal(f.Default))
+	}
+	lit := gen.literal(f.Default)
+	if boxed {
+		switch gen.registry.FindBaseType(f.Type) {
+		case rdl.BaseTypeInt64:
+			lit += "L"
+		case rdl.BaseTypeFloat32:
+			lit += "f"
+		case rdl.BaseTypeFloat64:
+			lit += "d"
+		}
+	}
+	return lit
+}
+
+// generateRecord writes the struct as a java record. The components holding a default value are boxed, so that the
+// compact constructor can tell when they are missing.
+func (gen *javaModelGenerator) generateRecord(t *rdl.Type, fields []*rdl.StructFieldDef, cName string, genAnnotations bool) {
+	tName, _, tComment := rdl.TypeInfo(t)
+	s := string(tName) + " -"
+	if tComment != "" {
+		s += " " + tComment
+	}
+	javadoc := utils.FormatJavadoc(s, 0, 80)
+	var params []string
+	for _, f := range fields {
+		if f.Comment != "" {
+			params = append(params, utils.FormatJavadoc(fmt.Sprintf("@param %s %s", javaFieldName(f.Name), f.Comment), 0, 80))
+		}
+	}
+	annotations := utils.TypeAnnotations(t)
+	if utils.IsDeprecated(annotations) {
+		params = append(params, utils.FormatJavadoc(utils.JavaDeprecatedTag(annotations), 0, 80))
+	}
+	if len(params) > 0 {
+		javadoc = strings.TrimSuffix(javadoc, " */\n") + " *\n"
+		for _, param := range params {
+			lines := strings.Split(strings.TrimSuffix(param, "\n"), "\n")
+			javadoc += strings.Join(lines[1:len(lines)-1], "\n") + "\n"
+		}
+		javadoc += " */\n"
+	}
+	gen.appendToBody(javadoc)
+	if utils.IsDeprecated(annotations) {
+		gen.appendToBody("@Deprecated\n")
+	}
+
+	gen.appendToBody(fmt.Sprintf("public record %s(", cName))
+	for i, f := range fields {
+		if i > 0 {
+			gen.appendToBody(",")
+		}
+		gen.appendToBody("\n")
+		if genAnnotations {
+			f.Annotations = gen.inlineDeprecation(f.Type, gen.constraintAnnotations(f.Type, f.Annotations))
+			for extendedKey, value := range f.Annotations {
+				if extendedKey == utils.SunsetAnnotationKey {
+					continue
+				}
+				gen.appendToBody("    ")
+				gen.generateValidationGroupAnnotation(extendedKey, value)
+				gen.appendToBody("\n")
+			}
+		} else if f.Annotations = gen.inlineDeprecation(f.Type, f.Annotations); utils.IsDeprecated(f.Annotations) {
+			gen.appendToBody("    @Deprecated\n")
+		}
+		gen.appendToBody("    ")
+		gen.generateStructFieldType(f.Type, f.Optional || f.Default != nil, f.Items, f.Keys)
+		gen.appendToBody(" " + javaFieldName(f.Name))
+	}
+	if len(fields) > 0 {
+		gen.appendToBody("\n")
+	}
+	gen.appendToBody(") implements java.io.Serializable {\n")
+
+	hasDefault := false
+	for _, f := range fields {
+		if f.Default != nil {
+			hasDefault = true
+		}
+	}
+	if hasDefault {
+		gen.appendToBody("\n    //\n    // sets up the missing components according to their default values\n    //\n")
+		gen.appendToBody(fmt.Sprintf("    public %s {\n", cName))
+		for _, f := range fields {
+			if f.Default != nil {
+				fname := javaFieldName(f.Name)
+				gen.appendToBody(fmt.Sprintf("        if (%s == null) {\n", fname))
+				gen.appendToBody(fmt.Sprintf("            %s = %s;\n", fname, gen.defaultValue(f, true)))
+				gen.appendToBody("        }\n")
+			}
+		}
+		gen.appendToBody("    }\n")
+	}
+	gen.appendToBody("}\n")
+}
+
+// generateImmutableStruct writes a value object: final fields set from a nested Builder, which Jackson also uses to
+// deserialize it, and field by field equals, hashCode and toString.
+func (gen *javaModelGenerator) generateImmutableStruct(st *rdl.StructTypeDef, fields []*rdl.StructFieldDef, cName string, genAnnotations bool) {
+	gen.appendImportClass("java.util.Objects")
+	gen.appendImportClass(JacksonDatabindPackage + ".annotation.JsonDeserialize")
+	gen.appendImportClass(JacksonDatabindPackage + ".annotation.JsonPOJOBuilder")
+
+	fnames := make([]string, 0, len(fields))
+	ftypes := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fnames = append(fnames, javaFieldName(f.Name))
+		ftypes = append(ftypes, gen.javaType(gen.registry, f.Type, f.Optional, f.Items, f.Keys))
+	}
+
+	gen.appendToBody(fmt.Sprintf("@JsonDeserialize(builder = %s.Builder.class)\n", cName))
+	gen.appendToBody(fmt.Sprintf("public final class %s implements java.io.Serializable {\n", cName))
+	gen.generateStructFields(fields, st.Name, st.Comment, cName, st.Annotations, genAnnotations)
+
+	// JAXB needs a no-arg constructor, Jackson goes through the builder
+	gen.appendToBody(fmt.Sprintf("\n    private %s() {\n", cName))
+	gen.appendToBody("        this(new Builder());\n")
+	gen.appendToBody("    }\n")
+	gen.appendToBody(fmt.Sprintf("\n    private %s(Builder builder) {\n", cName))
+	for i, fname := range fnames {
+		gen.appendToBody(fmt.Sprintf("        this.%s = %s;\n", fname, gen.immutableCopy(fields[i], "builder."+fname)))
+	}
+	gen.appendToBody("    }\n")
+	gen.appendToBody("\n    public static Builder builder() {\n")
+	gen.appendToBody("        return new Builder();\n")
+	gen.appendToBody("    }\n")
+	gen.appendToBody("\n    public Builder toBuilder() {\n")
+	gen.appendToBody("        return new Builder()")
+	for _, fname := range fnames {
+		gen.appendToBody(fmt.Sprintf("\n            .set%s(%s)", gen.accessorName(fname), fname))
+	}
+	gen.appendToBody(";\n")
+	gen.appendToBody("    }\n")
+
+	// equals, hashCode and toString
+	gen.appendToBody("\n    @Override\n")
+	gen.appendToBody("    public boolean equals(Object obj) {\n")
+	gen.appendToBody("        if (this == obj) {\n")
+	gen.appendToBody("            return true;\n")
+	gen.appendToBody("        }\n")
+	gen.appendToBody(fmt.Sprintf("        if (!(obj instanceof %s)) {\n", cName))
+	gen.appendToBody("            return false;\n")
+	gen.appendToBody("        }\n")
+	if len(fields) == 0 {
+		gen.appendToBody("        return true;\n")
+	} else {
+		gen.appendToBody(fmt.Sprintf("        %s other = (%s) obj;\n", cName, cName))
+		for i, fname := range fnames {
+			if i == 0 {
+				gen.appendToBody("        return ")
+			} else {
+				gen.appendToBody("\n            && ")
+			}
+			gen.appendToBody(fieldEquals(ftypes[i], fname))
+		}
+		gen.appendToBody(";\n")
+	}
+	gen.appendToBody("    }\n")
+	gen.appendToBody("\n    @Override\n")
+	gen.appendToBody("    public int hashCode() {\n")
+	gen.appendToBody(fmt.Sprintf("        return Objects.hash(%s);\n", strings.Join(fnames, ", ")))
+	gen.appendToBody("    }\n")
+	gen.appendToBody("\n    @Override\n")
+	gen.appendToBody("    public String toString() {\n")
+	gen.appendToBody(fmt.Sprintf("        return \"%s(\"", cName))
+	for i, fname := range fnames {
+		sep := ""
+		if i > 0 {
+			sep = ", "
+		}
+		gen.appendToBody(fmt.Sprintf("\n            + \"%s%s=\" + %s", sep, fname, fname))
+	}
+	gen.appendToBody("\n            + \")\";\n")
+	gen.appendToBody("    }\n")
+
+	// the builder
+	gen.appendToBody("\n    @JsonPOJOBuilder(withPrefix = \"set\")\n")
+	gen.appendToBody("    public static final class Builder {\n")
+	for i, fname := range fnames {
+		gen.appendToBody(fmt.Sprintf("        private %s %s;\n", ftypes[i], fname))
+	}
+	if gen.structHasFieldDefault(st) {
+		gen.generateInit(fields, "    ", "builder")
+		gen.appendToBody("\n        public Builder() { init(); }\n")
+	} else {
+		gen.appendToBody("\n        public Builder() {  }\n")
+	}
+	for i, fname := range fnames {
+		gen.appendToBody("\n")
+		gen.appendToBody(utils.JavaDeprecation(fields[i].Annotations, 8))
+		if genAnnotations {
+			for extendedKey, value := range fields[i].Annotations {
+				if strings.TrimLeft(string(extendedKey), AnnotationPrefix) == "name" {
+					gen.appendAnnotation("        @XmlElement", fmt.Sprintf("name=\"%s\"", value))
+					gen.appendToBody("\n")
+				}
+			}
+		}
+		gen.appendToBody(fmt.Sprintf("        public Builder set%s(%s %s) { this.%s = %s; return this; }\n", gen.accessorName(fname), ftypes[i], fname, fname, fname))
+	}
+	gen.appendToBody(fmt.Sprintf("\n        public %s build() {\n", cName))
+	gen.appendToBody(fmt.Sprintf("            return new %s(this);\n", cName))
+	gen.appendToBody("        }\n")
+	gen.appendToBody("    }\n")
+	gen.appendToBody("}\n")
+}
+
+// immutableCopy copies the List or Map of the builder into an unmodifiable one, so that the built object cannot change
+func (gen *javaModelGenerator) immutableCopy(f *rdl.StructFieldDef, expr string) string {
+	if gen.isValueType(f.Type) {
+		return expr
+	}
+	var wrap, impl string
+	switch gen.registry.FindBaseType(f.Type) {
+	case rdl.BaseTypeArray:
+		wrap, impl = "unmodifiableList", "ArrayList"
+	case rdl.BaseTypeMap:
+		wrap, impl = "unmodifiableMap", "LinkedHashMap"
+	default:
+		return expr
+	}
+	gen.appendImportClass("java.util.Collections")
+	gen.appendImportClass("java.util." + impl)
+	return fmt.Sprintf("%s == null ? null : Collections.%s(new %s<>(%s))", expr, wrap, impl, expr)
+}
+
+// fieldEquals compares a field of this and other: by value for the primitives, with Objects.equals otherwise
+func fieldEquals(ftype string, fname string) string {
+	switch ftype {
+	case "boolean", "byte", "short", "int", "long":
+		return fmt.Sprintf("%s == other.%s", fname, fname)
+	case "float":
+		return fmt.Sprintf("Float.compare(%s, other.%s) == 0", fname, fname)
+	case "double":
+		return fmt.Sprintf("Double.compare(%s, other.%s) == 0", fname, fname)
+	default:
+		return fmt.Sprintf("Objects.equals(%s, other.%s)", fname, fname)
+	}
+}
+
+// accessorName is the field name as it follows get and set, according to the naming style
+func (gen *javaModelGenerator) accessorName(fname string) string {
+	if gen.namingStyle == JavaBeanNamingStyle {
+		return javaBeanStyle(fname)
+	}
+	return upperFirst(fname)
+}
+
 func (gen *javaModelGenerator) generateEnum(t *rdl.Type) {
 	if gen.err != nil {
 		return
@@ -604,6 +922,7 @@ func (gen *javaModelGenerator) generateEnum(t *rdl.Type) {
 		name += JavaClassSuffix
 	}
 	gen.appendToBody(fmt.Sprintf("public enum %s {", name))
+	deprecated := utils.DeprecatedSymbols(et.Annotations)
 	for i, elem := range et.Elements {
 		sym := elem.Symbol
 		if i > 0 {
@@ -611,6 +930,9 @@ func (gen *javaModelGenerator) generateEnum(t *rdl.Type) {
 		} else {
 			gen.appendToBody("\n")
 		}
+		if deprecated[string(sym)] {
+			gen.appendToBody("    @Deprecated\n")
+		}
 		gen.appendToBody(fmt.Sprintf("    %s", sym))
 	}
 	gen.appendToBody(";\n")
@@ -635,15 +957,18 @@ func (gen *javaModelGenerator) generateStructFields(fields []*rdl.StructFieldDef
 			gen.appendToBody("\n")
 
 			if genAnnotations {
-				if len(f.Annotations) == 0 {
-					f.Annotations = utils.GetUserDefinedTypeAnnotations(f.Type, gen.schema.Types)
-				}
+				f.Annotations = gen.inlineDeprecation(f.Type, gen.constraintAnnotations(f.Type, f.Annotations))
 				fannotations = append(fannotations, f.Annotations)
 				for extendedKey, value := range f.Annotations {
+					if extendedKey == utils.SunsetAnnotationKey {
+						continue
+					}
 					gen.appendToBody("    ")
 					gen.generateValidationGroupAnnotation(extendedKey, value)
 					gen.appendToBody("\n")
 				}
+			} else {
+				f.Annotations = gen.inlineDeprecation(f.Type, f.Annotations)
 			}
 
 			fname := javaFieldName(f.Name)
@@ -653,23 +978,33 @@ func (gen *javaModelGenerator) generateStructFields(fields []*rdl.StructFieldDef
 			ftype := gen.javaType(gen.registry, f.Type, optional, f.Items, f.Keys)
 			ftypes = append(ftypes, ftype)
 
-			gen.appendToBody("    private ")
+			if gen.style == ImmutableStyle {
+				gen.appendToBody("    private final ")
+			} else {
+				gen.appendToBody("    private ")
+			}
 			gen.generateStructFieldType(f.Type, optional, f.Items, f.Keys)
 			gen.appendToBody(fmt.Sprintf(" %s;\n", fname))
 
 		}
 
 		gen.appendToBody("\n")
-		gen.appendToBody("    // This annotated field 'reserved' is used to handle the Moxy unmarshall error\n")
-		gen.appendToBody("    // case when user requests some unknown fields which are nullable.\n")
-		gen.appendToBody("    @XmlAnyElement(lax=true)\n")
-		gen.appendToBody("    private Object parsecReserved;")
-		gen.appendToBody("\n")
+		// an immutable class is only built by its builder, which Moxy does not unmarshall with
+		if gen.style != ImmutableStyle {
+			gen.appendImportClass(JavaxXmlBindAnnotationPackage + ".XmlAnyElement")
+			gen.appendToBody("    // This annotated field 'reserved' is used to handle the Moxy unmarshall error\n")
+			gen.appendToBody("    // case when user requests some unknown fields which are nullable.\n")
+			gen.appendToBody("    @XmlAnyElement(lax=true)\n")
+			gen.appendToBody("    private Object parsecReserved;")
+			gen.appendToBody("\n")
+		}
 		for i := range fields {
 			fname := fnames[i]
 			ftype := ftypes[i]
 			if genAnnotations {
 				gen.generateStructFieldGetterAnnotations(fannotations[i])
+			} else {
+				gen.appendToBody(utils.JavaDeprecation(fields[i].Annotations, 4))
 			}
 			switch gen.namingStyle {
 			case JavaBeanNamingStyle:
@@ -678,12 +1013,17 @@ func (gen *javaModelGenerator) generateStructFields(fields []*rdl.StructFieldDef
 				gen.appendToBody(fmt.Sprintf("    public %s get%s() { return %s; }\n", ftype, upperFirst(fname), fname))
 			}
 		}
+		if gen.style == ImmutableStyle {
+			return
+		}
 		gen.appendToBody("\n")
 		for i := range fields {
 			fname := fnames[i]
 			ftype := ftypes[i]
 			if genAnnotations {
 				gen.generateStructFieldSetterAnnotations(fannotations[i])
+			} else {
+				gen.appendToBody(utils.JavaDeprecation(fields[i].Annotations, 4))
 			}
 			switch gen.namingStyle {
 			case JavaBeanNamingStyle:
@@ -700,6 +1040,10 @@ func (gen *javaModelGenerator) generateStructFieldType(rdlType rdl.TypeRef, opti
 	if t == nil || t.Variant == 0 {
 		panic("Cannot find type '" + rdlType + "'")
 	}
+	if gen.isValueType(rdlType) {
+		gen.appendToBody(gen.javaType(gen.registry, rdlType, optional, items, keys))
+		return
+	}
 	bt := gen.registry.BaseType(t)
 	switch bt {
 	case rdl.BaseTypeArray:
@@ -741,7 +1085,7 @@ func (gen *javaModelGenerator) generateStructFieldType(rdlType rdl.TypeRef, opti
 }
 
 func (gen *javaModelGenerator) generateStructFieldParamType(rdlType rdl.TypeRef, optional bool, items rdl.TypeRef, keys rdl.TypeRef) {
-	annotations := utils.GetUserDefinedTypeAnnotations(rdlType, gen.schema.Types)
+	annotations := gen.constraintAnnotations(rdlType, nil)
 	if len(annotations) > 0 {
 		gen.appendToBody("\n")
 		for extendedKey, value := range annotations {
@@ -771,6 +1115,12 @@ func (gen *javaModelGenerator) generateValidationGroupAnnotation(extendedKey rdl
 	case "max":
 		gen.appendAnnotation("@Max", value)
 		gen.appendImportClass(JavaxConstraintPackage + ".Max")
+	case "decimal_min":
+		gen.appendAnnotation("@DecimalMin", value)
+		gen.appendImportClass(JavaxConstraintPackage + ".DecimalMin")
+	case "decimal_max":
+		gen.appendAnnotation("@DecimalMax", value)
+		gen.appendImportClass(JavaxConstraintPackage + ".DecimalMax")
 	case "size":
 		gen.appendAnnotation("@Size", value)
 		gen.appendImportClass(JavaxConstraintPackage + ".Size")
@@ -813,6 +1163,8 @@ func (gen *javaModelGenerator) generateValidationGroupAnnotation(extendedKey rdl
 	case "adapter":
 		gen.appendAnnotation("@XmlJavaTypeAdapter", value)
 		gen.appendImportClass(JavaxXmlBindAnnotationPackage + ".adapters.XmlJavaTypeAdapter")
+	case "deprecated":
+		gen.appendAnnotation("@Deprecated", "")
 	default:
 		// unrecognized annotation, do nothing
 	}
@@ -820,6 +1172,7 @@ func (gen *javaModelGenerator) generateValidationGroupAnnotation(extendedKey rdl
 
 func (gen *javaModelGenerator) generateStructFieldGetterAnnotations(annotations map[rdl.ExtendedAnnotation]string) {
 	gen.appendToBody("\n")
+	gen.appendToBody(utils.JavaDeprecation(annotations, 4))
 	for extendedKey, value := range annotations {
 		key := strings.TrimLeft(string(extendedKey), AnnotationPrefix)
 		switch key {
@@ -835,6 +1188,7 @@ func (gen *javaModelGenerator) generateStructFieldGetterAnnotations(annotations
 
 func (gen *javaModelGenerator) generateStructFieldSetterAnnotations(annotations map[rdl.ExtendedAnnotation]string) {
 	gen.appendToBody("\n")
+	gen.appendToBody(utils.JavaDeprecation(annotations, 4))
 	for extendedKey, value := range annotations {
 		key := strings.TrimLeft(string(extendedKey), AnnotationPrefix)
 		switch key {
@@ -856,7 +1210,7 @@ func (gen *javaModelGenerator) appendAnnotation(key string, value string) {
 }
 
 func (gen *javaModelGenerator) appendImportClass(importClass string) {
-	importString := fmt.Sprintf("import %s;\n", importClass)
+	importString := fmt.Sprintf("import %s;\n", utils.JavaEEClass(importClass, gen.jakarta))
 	alreadyExists := false
 	for _, value := range gen.imports {
 		if importString == value {
@@ -943,7 +1297,7 @@ func (gen *javaModelGenerator) getValidationGroupValue(annotationValue string) s
 func (gen *javaModelGenerator) javaType(reg rdl.TypeRegistry, rdlType rdl.TypeRef, optional bool, items rdl.TypeRef, keys rdl.TypeRef) string {
 	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
 	checkErr(err)
-	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver)
+	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver, gen.genValueTypes)
 }
 
 func javaFieldName(n rdl.Identifier) string {
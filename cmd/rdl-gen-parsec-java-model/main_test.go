@@ -235,7 +235,7 @@ func TestGenerateModelWithoutVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	GenerateJavaModel("withoutVersion", schema, testOutputDir, true, "", false, "upper_first")
+	GenerateJavaModel("withoutVersion", schema, testOutputDir, true, "", false, "upper_first", false, MutableStyle, false)
 
 	//asserts
 	content := checkAndGetFileContent(t, path, "User.java")
@@ -254,7 +254,7 @@ func TestGenerateModelWithVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	GenerateJavaModel("withVersion", schema, testOutputDir, true, "", false, "upper_first")
+	GenerateJavaModel("withVersion", schema, testOutputDir, true, "", false, "upper_first", false, MutableStyle, false)
 
 	//asserts
 	content := checkAndGetFileContent(t, path, "UserV2.java")
@@ -264,6 +264,234 @@ func TestGenerateModelWithVersion(t *testing.T) {
 	defer os.RemoveAll(testOutputDir)
 }
 
+func TestGenerateUnionModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleUnion.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("union", schema, testOutputDir, true, "", true, "upper_first", false, MutableStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "PetV2_Pc.java"))
+	assert.Contains(t, content, "public final class PetV2_Pc implements java.io.Serializable")
+	assert.Contains(t, content, "@JsonDeserialize(using = PetV2_Pc.Deserializer.class)")
+	assert.Contains(t, content, "public CatV2_Pc getCat() { return variant == Variant.Cat ? (CatV2_Pc) value : null; }")
+	assert.Contains(t, content, "if (hasFields(node, \"name\", \"goodBoy\")) {")
+	assert.NotContains(t, content, "nickname")
+
+	content = string(checkAndGetFileContent(t, path, "TagV2_Pc.java"))
+	assert.Contains(t, content, "return ofColor(ColorV2_Pc.fromString(node.asText()));")
+	assert.Contains(t, content, "return ofString(node.asText());")
+	assert.Contains(t, content, "return ofInt64(node.asLong());")
+
+	content = string(checkAndGetFileContent(t, path, "OwnerV2_Pc.java"))
+	assert.Contains(t, content, "private PetV2_Pc pet;")
+
+	content = string(checkAndGetFileContent(t, path, "AnimalV2_Pc.java"))
+	assert.Contains(t, content, "switch (node.path(\"kind\").asText()) {")
+	assert.Contains(t, content, "case \"Fish\":\n                        return ofFish(codec.treeToValue(node, FishV2_Pc.class));")
+	assert.NotContains(t, content, "hasFields")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateValueTypeModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("valueTypes", schema, testOutputDir, true, "", false, "upper_first", true, MutableStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Sku.java"))
+	assert.Contains(t, content, "public final class Sku implements java.io.Serializable")
+	assert.Contains(t, content, "@Pattern(regexp = \"[A-Z]{3}-[0-9]{4}\")\n    private final String value;")
+	assert.Contains(t, content, "@JsonCreator(mode = JsonCreator.Mode.DELEGATING)\n    public Sku(String value) {")
+	assert.Contains(t, content, "@JsonValue\n    public String getValue() { return value; }")
+
+	content = string(checkAndGetFileContent(t, path, "Currency.java"))
+	assert.Contains(t, content, "@Pattern(regexp = \"^(USD|EUR)$\")")
+
+	content = string(checkAndGetFileContent(t, path, "Quantity.java"))
+	assert.Contains(t, content, "@Max(100)\n    @Min(1)\n    private final int value;")
+
+	content = string(checkAndGetFileContent(t, path, "Price.java"))
+	assert.Contains(t, content, "@DecimalMin(\"0.01\")\n    private final double value;")
+
+	content = string(checkAndGetFileContent(t, path, "Skus.java"))
+	assert.Contains(t, content, "@Size(min = 1, max = 10)\n    private final List<Sku> value;")
+
+	content = string(checkAndGetFileContent(t, path, "Item.java"))
+	assert.Contains(t, content, "private Sku sku;")
+	assert.Contains(t, content, "quantity = new Quantity(1);")
+	assert.NotContains(t, content, "@Pattern")
+
+	content = string(checkAndGetFileContent(t, path, "Cart.java"))
+	assert.Contains(t, content, "private Skus skus;")
+	assert.Contains(t, content, "private List<Item> items;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateFacetConstraintsModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("facets", schema, testOutputDir, true, "", false, "upper_first", false, MutableStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Item.java"))
+	assert.Contains(t, content, "@Pattern(regexp = \"[A-Z]{3}-[0-9]{4}\")\n    private String sku;")
+	assert.Contains(t, content, "@Pattern(regexp = \"^(USD|EUR)$\")\n    private String currency;")
+	assert.Contains(t, content, "@DecimalMin(\"0.01\")\n    private double price;")
+	assert.Contains(t, content, "import javax.validation.constraints.DecimalMin;")
+
+	content = string(checkAndGetFileContent(t, path, "Cart.java"))
+	assert.Contains(t, content, "@Size(min = 1, max = 10)\n    private List<\n    @Pattern(regexp = \"[A-Z]{3}-[0-9]{4}\")\n        String> skus;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateImmutableModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("immutable", schema, testOutputDir, true, "", false, "upper_first", false, ImmutableStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Item.java"))
+	assert.Contains(t, content, "@JsonDeserialize(builder = Item.Builder.class)\npublic final class Item implements java.io.Serializable {")
+	assert.Contains(t, content, "    private final String sku;")
+	assert.Contains(t, content, "    public String getSku() { return sku; }")
+	assert.NotContains(t, content, "public Item setSku(")
+	assert.Contains(t, content, "        this.quantity = builder.quantity;")
+	assert.Contains(t, content, "        return new Builder()\n            .setSku(sku)\n            .setQuantity(quantity)")
+	assert.Contains(t, content, "        return Objects.equals(sku, other.sku)\n            && quantity == other.quantity\n            && Double.compare(price, other.price) == 0")
+	assert.Contains(t, content, "        return Objects.hash(sku, quantity, price, currency);")
+	assert.Contains(t, content, "            + \", quantity=\" + quantity")
+	assert.Contains(t, content, "    @JsonPOJOBuilder(withPrefix = \"set\")\n    public static final class Builder {")
+	assert.Contains(t, content, "        private void init() {\n            quantity = 1;\n        }")
+	assert.Contains(t, content, "        public Builder setQuantity(int quantity) { this.quantity = quantity; return this; }")
+	assert.NotContains(t, content, "EqualsBuilder")
+	assert.NotContains(t, content, "parsecReserved")
+	assert.NotContains(t, content, "XmlAnyElement")
+
+	// the collections of an immutable object cannot be changed either
+	schema, err = rdl.ParseRDLFile("../../testdata/sampleRecord.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("immutable", schema, testOutputDir, true, "", false, "upper_first", false, ImmutableStyle, false)
+	content = string(checkAndGetFileContent(t, path, "Product.java"))
+	assert.Contains(t, content, "        this.tags = builder.tags == null ? null : Collections.unmodifiableList(new ArrayList<>(builder.tags));")
+	assert.Contains(t, content, "        this.name = builder.name;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateRecordModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleRecord.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("record", schema, testOutputDir, true, "", false, "upper_first", false, RecordStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Product.java"))
+	assert.Contains(t, content, "/**\n * Product - A product in the catalog\n *\n * @param tags the tags of the product, for search\n */\npublic record Product(")
+	assert.Contains(t, content, "    @Size(min = 1)\n    String name,\n")
+	assert.Contains(t, content, "    Integer stock,\n")
+	assert.Contains(t, content, "    Color color,\n    List<String> tags\n) implements java.io.Serializable {")
+	assert.Contains(t, content, "    public Product {\n        if (stock == null) {\n            stock = 10;\n        }")
+	assert.Contains(t, content, "            price = 9.99d;")
+	assert.Contains(t, content, "            views = 0L;")
+	assert.NotContains(t, content, "XmlAnyElement")
+	assert.NotContains(t, content, "org.apache.commons")
+
+	content = string(checkAndGetFileContent(t, path, "Color.java"))
+	assert.Contains(t, content, "public enum Color {")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateJakartaModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("jakarta", schema, testOutputDir, true, "", false, "upper_first", false, MutableStyle, true)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Item.java"))
+	assert.Contains(t, content, "import jakarta.validation.constraints.Pattern;")
+	assert.Contains(t, content, "import jakarta.xml.bind.annotation.XmlAnyElement;")
+	assert.NotContains(t, content, "import javax.")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateDeprecatedModel(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleDeprecation.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaModel("deprecation", schema, testOutputDir, true, "", false, "upper_first", false, MutableStyle, false)
+
+	//asserts
+	content := string(checkAndGetFileContent(t, path, "Item.java"))
+	assert.Contains(t, content, "    @Deprecated\n    private String label;")
+	assert.Contains(t, content, "    /**\n     * @deprecated Sunset on 2027-06-30.\n     */\n    @Deprecated\n    public String getLabel()")
+	assert.Contains(t, content, "    /**\n     * @deprecated Sunset on 2027-06-30.\n     */\n    @Deprecated\n    public Item setLabel(")
+	// the string typedef is inlined, so the field carries its deprecation
+	assert.Contains(t, content, "     * @deprecated Use the sku instead\n     */\n    @Deprecated\n    public String getCode()")
+	assert.NotContains(t, content, "x_sunset")
+
+	content = string(checkAndGetFileContent(t, path, "OldItem.java"))
+	assert.Contains(t, content, "/**\n * @deprecated Use Item instead. Sunset on 2027-06-30.\n */\n@Deprecated\npublic final class OldItem")
+
+	content = string(checkAndGetFileContent(t, path, "Status.java"))
+	assert.Contains(t, content, "    ACTIVE,\n    RETIRED,\n    @Deprecated\n    PENDING;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
 func checkAndGetFileContent(t *testing.T, path string, fileName string) []byte {
 	//1. check correspanding client file exists
 	if _, err := os.Stat(path + fileName); err != nil {
@@ -3,6 +3,7 @@ package main
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ardielle/ardielle-go/rdl"
@@ -19,7 +20,7 @@ func TestGenerateServerWithoutVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%v", err)
 	}
-	GenerateJavaServer("withoutVersion", schema, testOutputDir, true, true, true, true, string(schema.Namespace), false)
+	GenerateJavaServer("withoutVersion", schema, testOutputDir, true, true, true, true, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
 
 	//asserts
 	resourcesContent := checkAndGetFileContent(t, path, "SampleResources.java")
@@ -35,12 +36,438 @@ func TestGenerateServerWithoutVersion(t *testing.T) {
 	serverContent := checkAndGetFileContent(t, path, "SampleServer.java")
 	assert.Contains(t, string(serverContent), "class SampleServer")
 	assert.Contains(t, string(serverContent), "bind(handler).to(SampleHandler.class)")
+	assert.Contains(t, string(serverContent), "bind(handlerClass).to(SampleHandler.class).in(Singleton.class)")
+	assert.Contains(t, string(serverContent), "bind(authenticator != null ? authenticator : Authenticator.REJECT_ALL).to(Authenticator.class)")
+	assert.Contains(t, string(serverContent), "bind(authorizer != null ? authorizer : Authorizer.DENY_ALL).to(Authorizer.class)")
+	assert.NotContains(t, string(serverContent), "if (authenticator != null)")
 
 	hImplContent := checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java")
 	assert.Contains(t, string(hImplContent), "class SampleHandlerImpl")
 	assert.Contains(t, string(hImplContent), "implements SampleHandler")
 	assert.Contains(t, string(hImplContent), "public User postUsers(ResourceContext context, User user)")
 	assert.Contains(t, string(hImplContent), "public User getUsersById(ResourceContext context, Integer id)")
+	assert.Contains(t, string(hImplContent), "import com.yahoo.shopping.parsec_generated.DefaultResourceContext;")
+	assert.Contains(t, string(hImplContent), "@Inject\n    public SampleHandlerImpl(Authenticator authenticator, Authorizer authorizer)")
+	assert.Contains(t, string(hImplContent), "return new DefaultResourceContext(request, response, authenticator, authorizer);")
+
+	contextContent := string(checkAndGetFileContent(t, path, "DefaultResourceContext.java"))
+	assert.Contains(t, contextContent, "public class DefaultResourceContext implements ResourceContext")
+	assert.Contains(t, contextContent, "principal = authenticator.authenticate(request);")
+	assert.Contains(t, contextContent, "!authorizer.authorize(action, resource, principal, trustedDomain)")
+	authenticatorContent := string(checkAndGetFileContent(t, path, "Authenticator.java"))
+	assert.Contains(t, authenticatorContent, "public Principal authenticate(HttpServletRequest request);")
+	assert.Contains(t, authenticatorContent, "public static final Authenticator REJECT_ALL = request -> null;")
+	authorizerContent := string(checkAndGetFileContent(t, path, "Authorizer.java"))
+	assert.Contains(t, authorizerContent, "public boolean authorize(String action, String resource, Principal principal, String trustedDomain);")
+	assert.Contains(t, authorizerContent, "public static final Authorizer DENY_ALL = (action, resource, principal, trustedDomain) -> false;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+	defer os.RemoveAll("./src")
+}
+
+func TestGenerateSpringServer(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+	srcPath := "./src/main/java/com/yahoo/shopping/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("spring", schema, testOutputDir, true, true, true, false, string(schema.Namespace), false, false, SpringFramework, AsyncResultMode, false, false)
+
+	//asserts
+	if _, err := os.Stat(path + "SampleResources.java"); err == nil {
+		t.Errorf("jax-rs resources should not be generated for spring")
+	}
+	if _, err := os.Stat(path + "SampleServer.java"); err == nil {
+		t.Errorf("jetty server should not be generated for spring")
+	}
+
+	controllerContent := string(checkAndGetFileContent(t, path, "SampleController.java"))
+	assert.Contains(t, controllerContent, "@RestController")
+	assert.Contains(t, controllerContent, "@RequestMapping(\"/Sample\")")
+	assert.Contains(t, controllerContent, "@GetMapping(value = \"/users/{name}\", produces = \"application/json;charset=utf-8\")")
+	assert.Contains(t, controllerContent, "@Pattern(regexp = \"[a-z]+\") @PathVariable(\"name\") String name")
+	assert.Contains(t, controllerContent, "@RequestParam(value = \"verbose\", required = false, defaultValue = \"false\") Boolean verbose")
+	assert.Contains(t, controllerContent, "@RequestHeader(value = \"X-Trace\", required = false) String trace")
+	assert.Contains(t, controllerContent, "@PutMapping(value = \"/users/{name}\", produces = \"application/json;charset=utf-8\", consumes = \"application/json;charset=utf-8\")")
+	assert.Contains(t, controllerContent, "@RequestBody User user")
+	assert.Contains(t, controllerContent, "_context.authorize(\"read\", \"user.\"+name+\"\", null);")
+	assert.Contains(t, controllerContent, "_context.authorize(\"delete\", \"user.\"+name+\"\", \"shopping\");")
+	assert.Contains(t, controllerContent, "_context.authenticate();")
+	assert.Contains(t, controllerContent, "return ResponseEntity.status(ResourceException.OK).body(e);")
+	assert.Contains(t, controllerContent, "case ResourceException.NOT_FOUND:\n                return typedResponse(_code, e, ResourceError.class);")
+	assert.Contains(t, controllerContent, "return result.response();")
+	assert.Contains(t, controllerContent, "public DeferredResult<ResponseEntity<Object>> getUsersByNameChanges(")
+	assert.Contains(t, controllerContent, "@Autowired private SampleHandler _delegate;")
+
+	handlerContent := string(checkAndGetFileContent(t, path, "SampleHandler.java"))
+	assert.Contains(t, handlerContent, "public void putUsersByName(ResourceContext context, String name, User user, PutUsersByNameResult result)")
+	assert.NotContains(t, handlerContent, "AsyncResponse")
+
+	hImplContent := string(checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java"))
+	assert.Contains(t, hImplContent, "@Component\npublic class SampleHandlerImpl implements SampleHandler")
+	assert.Contains(t, hImplContent, "@Autowired\n    public SampleHandlerImpl(@Nullable Authenticator authenticator, @Nullable Authorizer authorizer) {")
+	assert.NotContains(t, hImplContent, "@Inject")
+
+	adviceContent := string(checkAndGetFileContent(t, path, "SampleControllerAdvice.java"))
+	assert.Contains(t, adviceContent, "@RestControllerAdvice(assignableTypes = SampleController.class)")
+	assert.Contains(t, adviceContent, "@ExceptionHandler(ResourceException.class)")
+	assert.Contains(t, adviceContent, "return ResponseEntity.status(e.getCode()).body(data);")
+
+	resultContent := string(checkAndGetFileContent(t, path, "PutUsersByNameResult.java"))
+	assert.Contains(t, resultContent, "public final class PutUsersByNameResult")
+	assert.Contains(t, resultContent, ".header(\"ETag\", String.valueOf(etag))")
+
+	asyncResultContent := string(checkAndGetFileContent(t, path, "GetUsersByNameChangesResult.java"))
+	assert.Contains(t, asyncResultContent, "_async.setResult(response);")
+	assert.Contains(t, asyncResultContent, "_async.onTimeout(this::handleTimeout);")
+	assert.Contains(t, asyncResultContent, "_async = new DeferredResult<>(_timeout * 1000L);")
+	assert.Contains(t, controllerContent, "return result.deferred();")
+	assert.NotContains(t, controllerContent, "new DeferredResult<>()")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+	defer os.RemoveAll("./src")
+}
+
+func TestGenerateServerResultClassName(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("result", schema, testOutputDir, true, false, true, false, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
+
+	//asserts
+	resultContent := string(checkAndGetFileContent(t, path, "PutUsersByNameResult.java"))
+	assert.Contains(t, resultContent, "public final class PutUsersByNameResult")
+	assert.NotContains(t, resultContent, "PutUserResult")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateServerFacetConstraints(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("facets", schema, testOutputDir, true, false, true, true, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
+
+	//asserts
+	resourcesContent := string(checkAndGetFileContent(t, path, "SampleResources.java"))
+	assert.Contains(t, resourcesContent, "import javax.validation.constraints.Pattern;")
+	assert.Contains(t, resourcesContent, "import javax.validation.constraints.DecimalMin;")
+	assert.Contains(t, resourcesContent, "@Pattern(regexp = \"[A-Z]{3}-[0-9]{4}\") @PathParam(\"sku\") String sku")
+	assert.Contains(t, resourcesContent, "@DecimalMin(\"0.01\") @QueryParam(\"price\") Double price")
+	// the explicit x_min overrides the derived one
+	assert.Contains(t, resourcesContent, "@Min(2) ")
+	assert.NotContains(t, resourcesContent, "@Min(1)")
+	assert.Equal(t, 1, strings.Count(resourcesContent, "@Max(100) "))
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateServerValueTypes(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+	srcPath := "./src/main/java/com/yahoo/shopping/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleValueTypes.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("valueTypes", schema, testOutputDir, true, true, true, true, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, true)
+
+	//asserts
+	handlerContent := string(checkAndGetFileContent(t, path, "SampleHandler.java"))
+	assert.Contains(t, handlerContent, "public Item getItemsBySku(ResourceContext context, Sku sku, Quantity quantity, Price price)")
+	assert.Contains(t, handlerContent, "public Cart getCarts(ResourceContext context, Skus skus)")
+
+	resourcesContent := string(checkAndGetFileContent(t, path, "SampleResources.java"))
+	// the value types validate themselves
+	assert.Contains(t, resourcesContent, "@Valid @PathParam(\"sku\") Sku sku")
+	assert.NotContains(t, resourcesContent, "@Pattern")
+	assert.Contains(t, resourcesContent, "@Valid @QueryParam(\"skus\") List<@Valid Sku> skus")
+	assert.Contains(t, resourcesContent, "skus == null ? null : new Skus(skus)")
+
+	hImplContent := string(checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java"))
+	assert.Contains(t, hImplContent, "import com.yahoo.shopping.parsec_generated.Sku;")
+	assert.Contains(t, hImplContent, "import com.yahoo.shopping.parsec_generated.Skus;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+	defer os.RemoveAll("./src")
+}
+
+func TestGenerateServerNamespaces(t *testing.T) {
+	senarios := []struct {
+		jakarta  bool
+		expected string
+	}{
+		{false, "../../testdata/SampleResources.txt"},
+		{true, "../../testdata/SampleResourcesJakarta.txt"},
+	}
+	for _, senario := range senarios {
+		testOutputDir := getTempDir(t, ".", "testOutput-")
+		path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+		//generate output result
+		schema, err := rdl.ParseRDLFile("../../testdata/sampleWithoutVersion.rdl", false, false, false)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		GenerateJavaServer("test", schema, testOutputDir, true, false, true, false, string(schema.Namespace), false, senario.jakarta, JaxRsFramework, AsyncResultMode, false, false)
+
+		//asserts
+		expected, err := ioutil.ReadFile(senario.expected)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		assert.Equal(t, string(expected), string(checkAndGetFileContent(t, path, "SampleResources.java")))
+
+		// clean up folder
+		os.RemoveAll(testOutputDir)
+	}
+}
+
+func TestGenerateServerDeprecation(t *testing.T) {
+	for _, framework := range []string{JaxRsFramework, SpringFramework} {
+		testOutputDir := getTempDir(t, ".", "testOutput-")
+		path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+		//generate output result
+		schema, err := rdl.ParseRDLFile("../../testdata/sampleDeprecation.rdl", false, false, false)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		GenerateJavaServer("deprecation", schema, testOutputDir, true, false, true, true, string(schema.Namespace), false, false, framework, AsyncResultMode, false, false)
+
+		//asserts
+		fileName := "SampleResources.java"
+		if framework == SpringFramework {
+			fileName = "SampleController.java"
+		}
+		content := string(checkAndGetFileContent(t, path, fileName))
+		assert.Contains(t, content, "            _response.setHeader(\"Deprecation\", \"true\");\n"+
+			"            _response.setHeader(\"Sunset\", \"Wed, 30 Jun 2027 00:00:00 GMT\");\n"+
+			"            Item e = _delegate.getLegacyItem(_context, code);")
+		assert.Equal(t, 1, strings.Count(content, "Deprecation"))
+
+		// clean up folder
+		os.RemoveAll(testOutputDir)
+	}
+}
+
+func TestGenerateServerExceptionMappers(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("mappers", schema, testOutputDir, true, false, true, true, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
+
+	//asserts
+	violationContent := string(checkAndGetFileContent(t, path, "ConstraintViolationExceptionMapper.java"))
+	assert.Contains(t, violationContent, "public class ConstraintViolationExceptionMapper implements ExceptionMapper<ConstraintViolationException>")
+	assert.Contains(t, violationContent, ".setMessage(violation.getMessage())")
+	assert.Contains(t, violationContent, ".setInvalidValue(invalidValue == null ? null : String.valueOf(invalidValue))")
+
+	resourceContent := string(checkAndGetFileContent(t, path, "ResourceExceptionMapper.java"))
+	assert.Contains(t, resourceContent, "public class ResourceExceptionMapper implements ExceptionMapper<ResourceException>")
+	assert.Contains(t, resourceContent, "    static {\n        declare(\"getUsersByName\", ResourceException.NOT_FOUND, ResourceError.class);\n    }")
+	assert.Contains(t, resourceContent, "new ParsecResourceError().setError(body)")
+
+	serverContent := string(checkAndGetFileContent(t, path, "SampleServer.java"))
+	assert.Contains(t, serverContent, ".register(ConstraintViolationExceptionMapper.class)\n                .register(ResourceExceptionMapper.class);")
+
+	// the undeclared codes are left to the ResourceExceptionMapper
+	resourcesContent := string(checkAndGetFileContent(t, path, "SampleResources.java"))
+	assert.Contains(t, resourcesContent, "            default:\n"+
+		"                System.err.println(\"*** Warning: undeclared exception (\"+_code+\") for resource getUsersByName\");\n"+
+		"                throw e;\n")
+	assert.Contains(t, resourcesContent, "            case ResourceException.NOT_FOUND:\n"+
+		"                throw typedException(_code, e, ResourceError.class);\n")
+
+	// clean up folder
+	os.RemoveAll(testOutputDir)
+
+	// spring has no ExceptionMapper
+	testOutputDir = getTempDir(t, ".", "testOutput-")
+	path = testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+	GenerateJavaServer("mappers", schema, testOutputDir, true, false, true, true, string(schema.Namespace), false, false, SpringFramework, AsyncResultMode, false, false)
+	if _, err := os.Stat(path + "ResourceExceptionMapper.java"); err == nil {
+		t.Errorf("exception mappers should not be generated for spring")
+	}
+	controllerContent := string(checkAndGetFileContent(t, path, "SampleController.java"))
+	assert.NotContains(t, controllerContent, "throw e;")
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestGenerateServerWaitRegistry(t *testing.T) {
+	for _, framework := range []string{JaxRsFramework, SpringFramework} {
+		testOutputDir := getTempDir(t, ".", "testOutput-")
+		path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+		//generate output result
+		schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		GenerateJavaServer("wait", schema, testOutputDir, true, false, true, false, string(schema.Namespace), false, false, framework, AsyncResultMode, false, false)
+
+		//asserts
+		resultContent := string(checkAndGetFileContent(t, path, "GetUsersByNameChangesResult.java"))
+		assert.Contains(t, resultContent, "WaitRegistry.Waiter {")
+		assert.Contains(t, resultContent, "public void wait(String name, String trace, int _timeout, int _normalStatus, int _timeoutStatus)")
+		assert.Contains(t, resultContent, "WaitRegistry.get().register(\"GetUsersByNameChangesResult\", WaitRegistry.key(name, trace), this);")
+		assert.Contains(t, resultContent, "if (WaitRegistry.get().unregister(\"GetUsersByNameChangesResult\", WaitRegistry.key(name, trace), this)) {")
+		assert.Contains(t, resultContent, "done(code, (User) _result[0]);")
+		assert.Contains(t, resultContent, "public static void notify(String name, String trace, User userObject) {\n"+
+			"        WaitRegistry.get().notify(\"GetUsersByNameChangesResult\", WaitRegistry.key(name, trace), new Object[] {userObject});")
+		assert.NotContains(t, resultContent, "_waiters")
+
+		assert.Contains(t, string(checkAndGetFileContent(t, path, "WaitRegistry.java")), "private static volatile WaitRegistry registry = new InMemoryWaitRegistry();")
+		assert.Contains(t, string(checkAndGetFileContent(t, path, "InMemoryWaitRegistry.java")), "public class InMemoryWaitRegistry implements WaitRegistry")
+
+		// clean up folder
+		os.RemoveAll(testOutputDir)
+	}
+}
+
+func TestGenerateServerFutures(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	path := testOutputDir + "/com/yahoo/shopping/parsec_generated/"
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = GenerateJavaServer("future", schema, testOutputDir, true, false, true, false, string(schema.Namespace), false, false, JaxRsFramework, AsyncFutureMode, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	//asserts
+	handlerContent := string(checkAndGetFileContent(t, path, "SampleHandler.java"))
+	assert.Contains(t, handlerContent, "import java.util.concurrent.CompletableFuture;")
+	assert.Contains(t, handlerContent, "public CompletableFuture<User> getUsersByName(ResourceContext context, String name, Boolean verbose, String trace);")
+	assert.Contains(t, handlerContent, "public CompletableFuture<PutUsersByNameResult> putUsersByName(ResourceContext context, String name, User user);")
+	assert.Contains(t, handlerContent, "public CompletableFuture<User> getUsersByNameChanges(ResourceContext context, String name, String trace);")
+	assert.Contains(t, handlerContent, "public CompletableFuture<Void> deleteUsersByName(ResourceContext context, String name);")
+	if _, err := os.Stat(path + "GetUsersByNameChangesResult.java"); err == nil {
+		t.Errorf("GetUsersByNameChangesResult.java should not be generated for futures")
+	}
+	// the output headers come with the entity
+	resultContent := string(checkAndGetFileContent(t, path, "PutUsersByNameResult.java"))
+	assert.Contains(t, resultContent, "public PutUsersByNameResult(User user, String etag) {")
+	assert.Contains(t, resultContent, "public String getEtag() {")
+	assert.NotContains(t, resultContent, "ResourceContext")
+
+	resourcesContent := string(checkAndGetFileContent(t, path, "SampleResources.java"))
+	assert.Contains(t, resourcesContent, "public void getUsersByName(@Suspended AsyncResponse asyncResp, ")
+	assert.Contains(t, resourcesContent, "        CompletableFuture<User> _future;\n"+
+		"        try {\n"+
+		"            ResourceContext _context = _delegate.newResourceContext(_request, _response);\n"+
+		"            _context.authorize(\"read\", \"user.\"+name+\"\", null);\n"+
+		"            _future = _delegate.getUsersByName(_context, name, verbose, trace);\n"+
+		"        } catch (ResourceException e) {\n")
+	assert.Contains(t, resourcesContent, "asyncResp.setTimeout(30000, TimeUnit.MILLISECONDS);\n"+
+		"        CompletableFuture<User> _future;")
+	assert.Equal(t, 1, strings.Count(resourcesContent, "asyncResp.setTimeout("))
+	assert.Contains(t, resourcesContent, "Response.status(ResourceException.OK).entity(_result).build()")
+	assert.Contains(t, resourcesContent, "            case ResourceException.NOT_FOUND:\n"+
+		"                asyncResp.resume(typedException(_code, e, ResourceError.class));\n"+
+		"                break;\n")
+	assert.Contains(t, resourcesContent, "asyncResp.resume(Response.noContent().build());")
+	assert.Contains(t, resourcesContent, "Response.status(ResourceException.OK).entity(_result.getUser()).header(\"ETag\", _result.getEtag()).build()")
+
+	// futures are bridged to AsyncResponse, which spring does not have
+	err = GenerateJavaServer("future", schema, testOutputDir, true, false, true, false, string(schema.Namespace), false, false, SpringFramework, AsyncFutureMode, false, false)
+	assert.NotNil(t, err)
+	assert.NotNil(t, checkModes(SpringFramework, AsyncFutureMode))
+	assert.Nil(t, checkModes(JaxRsFramework, AsyncFutureMode))
+
+	// clean up folder
+	defer os.RemoveAll(testOutputDir)
+}
+
+func TestMergeHandlerImpl(t *testing.T) {
+	testOutputDir := getTempDir(t, ".", "testOutput-")
+	srcPath := "./src/main/java/com/yahoo/shopping/"
+	os.RemoveAll("./src")
+
+	//generate output result
+	schema, err := rdl.ParseRDLFile("../../testdata/sampleServer.rdl", false, false, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	GenerateJavaServer("merge", schema, testOutputDir, true, true, true, false, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
+	hImplContent := string(checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java"))
+	assert.Contains(t, hImplContent, "    @Override\n"+
+		"    public void deleteUsersByName(ResourceContext context, String name) {\n"+
+		"    }\n")
+
+	// hand-written bodies, a resource added to the RDL and an outdated method
+	hImplContent = strings.Replace(hImplContent, "String trace) {\n        return null;", "String trace) {\n        return new User().setName(name);", 1)
+	i := strings.Index(hImplContent, "\n    @Override\n    public void deleteUsersByName(")
+	j := i + strings.Index(hImplContent[i:], "    }\n") + len("    }\n")
+	hImplContent = hImplContent[:i] + hImplContent[j:]
+	hImplContent = strings.Replace(hImplContent, "String name, User user, PutUsersByNameResult result)", "String name, PutUsersByNameResult result)", 1)
+	hImplContent = strings.Replace(hImplContent, "import com.yahoo.shopping.parsec_generated.User;\n", "", 1)
+	os.WriteFile(srcPath+"SampleHandlerImpl.java", []byte(hImplContent), 0644)
+
+	err = GenerateJavaServer("merge", schema, testOutputDir, true, true, true, false, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, true, false)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	merged := string(checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java"))
+	assert.Contains(t, merged, "return new User().setName(name);")
+	assert.Contains(t, merged, "String name, PutUsersByNameResult result)")
+	assert.Contains(t, merged, "import com.yahoo.shopping.parsec_generated.User;\n")
+	assert.True(t, strings.HasSuffix(merged, "    }\n\n"+
+		"    @Override\n"+
+		"    public void deleteUsersByName(ResourceContext context, String name) {\n"+
+		"    }\n}\n"))
+	assert.Equal(t, 1, strings.Count(merged, "deleteUsersByName"))
+
+	// merging again has nothing to add
+	gen := &javaServerGenerator{
+		registry:       rdl.NewTypeRegistry(schema),
+		schema:         schema,
+		name:           "Sample",
+		banner:         "merge",
+		genAnnotations: true,
+		genUsingPath:   true,
+		namespace:      string(schema.Namespace),
+		framework:      JaxRsFramework,
+		async:          AsyncResultMode,
+	}
+	warnings, err := gen.mergeHandlerImpl(srcPath + "SampleHandlerImpl.java")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"putUsersByName changed in " + srcPath + "SampleHandlerImpl.java: " +
+		"void putUsersByName(ResourceContext, String, User, PutUsersByNameResult), was void putUsersByName(ResourceContext, String, PutUsersByNameResult)"}, warnings)
+	assert.Equal(t, merged, string(checkAndGetFileContent(t, srcPath, "SampleHandlerImpl.java")))
 
 	// clean up folder
 	defer os.RemoveAll(testOutputDir)
@@ -58,7 +485,7 @@ func TestGenerateServerWithVersion(t *testing.T) {
 		t.Fatalf("%v", err)
 	}
 
-	GenerateJavaServer("withVersion", schema, testOutputDir, true, true, true, true, string(schema.Namespace), false)
+	GenerateJavaServer("withVersion", schema, testOutputDir, true, true, true, true, string(schema.Namespace), false, false, JaxRsFramework, AsyncResultMode, false, false)
 
 	//asserts
 	resourcesContent := checkAndGetFileContent(t, path, "SampleV2Resources.java")
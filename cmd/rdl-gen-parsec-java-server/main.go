@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/ardielle/ardielle-go/rdl"
 	"github.com/yahoo/parsec-rdl-gen/utils"
@@ -29,6 +30,12 @@ const (
 	HibernateConstraintPackage = "org.hibernate.validator.constraints"
 	ParsecConstraintPackage    = "com.yahoo.parsec.constraint.validators"
 	ValidationGroupsClass      = "ParsecValidationGroups"
+	JaxRsFramework             = "jaxrs"
+	SpringFramework            = "spring"
+	AsyncResultMode            = "result"
+	AsyncFutureMode            = "future"
+	TimeoutAnnotationKey       = "x_timeout"
+	WaitKeyAnnotationKey       = "x_wait_key"
 )
 
 // Version is set when building to contain the build version
@@ -49,6 +56,11 @@ type javaServerGenerator struct {
 	genUsingPath   bool
 	namespace      string
 	isPcSuffix     bool
+	jakarta        bool
+	framework      string
+	async          string
+	genParsecError bool
+	genValueTypes  bool
 }
 
 func main() {
@@ -57,10 +69,15 @@ func main() {
 	genAnnotationsString := flag.String("a", "true", "Generate annotations")
 	genUsingPathString := flag.String("p", "true", "Generate using path")
 	genHandlerImplString := flag.String("i", "true", "Generate interface implementations")
+	mergeHandlerImplString := flag.String("merge", "false", "Add the stubs of new resources to an existing interface implementation")
 	genParsecErrorString := flag.String("e", "true", "Generate Parsec Error classes")
 	namespace := flag.String("ns", "", "Namespace")
 	pc := flag.String("pc", "false", "add '_Pc' postfix to the generated java class")
 	dataFile := flag.String("df", "", "JSON representation of the schema file")
+	jakartaString := flag.String("jakarta", "false", utils.JakartaFlagUsage)
+	framework := flag.String("framework", JaxRsFramework, "Server framework to generate the glue code for: jaxrs or spring")
+	async := flag.String("async", AsyncResultMode, "How handlers answer: result (result classes for async resources) or future (every handler returns a CompletableFuture)")
+	vt := flag.String("vt", "false", "use the value types of the named array, map, string and number types, as generated by parsec-java-model -vt")
 	flag.Parse()
 
 	genAnnotations, err := strconv.ParseBool(*genAnnotationsString)
@@ -69,10 +86,17 @@ func main() {
 	checkErr(err)
 	genHandlerImpl, err := strconv.ParseBool(*genHandlerImplString)
 	checkErr(err)
+	mergeHandlerImpl, err := strconv.ParseBool(*mergeHandlerImplString)
+	checkErr(err)
 	genParsecError, err := strconv.ParseBool(*genParsecErrorString)
 	checkErr(err)
 	isPcSuffix, err := strconv.ParseBool(*pc)
 	checkErr(err)
+	jakarta, err := strconv.ParseBool(*jakartaString)
+	checkErr(err)
+	genValueTypes, err := strconv.ParseBool(*vt)
+	checkErr(err)
+	checkErr(checkModes(*framework, *async))
 
 	var data []byte
 	if *dataFile != "" {
@@ -89,7 +113,8 @@ func main() {
 		var schema rdl.Schema
 		err = json.Unmarshal(data, &schema)
 		if err == nil {
-			GenerateJavaServer(banner, &schema, *pOutdir, genAnnotations, genHandlerImpl, genUsingPath, genParsecError, *namespace, isPcSuffix)
+			err = GenerateJavaServer(banner, &schema, *pOutdir, genAnnotations, genHandlerImpl, genUsingPath, genParsecError, *namespace, isPcSuffix, jakarta, *framework, *async, mergeHandlerImpl, genValueTypes)
+			checkErr(err)
 			os.Exit(0)
 		}
 	}
@@ -104,8 +129,25 @@ func checkErr(err error) {
 	}
 }
 
+// checkModes rejects the unknown frameworks and async modes, and the combinations of them that are not supported
+func checkModes(framework string, async string) error {
+	if framework != JaxRsFramework && framework != SpringFramework {
+		return fmt.Errorf("unsupported framework: %s", framework)
+	}
+	if async != AsyncResultMode && async != AsyncFutureMode {
+		return fmt.Errorf("unsupported async mode: %s", async)
+	}
+	if async == AsyncFutureMode && framework == SpringFramework {
+		return fmt.Errorf("the %s async mode is only supported by the %s framework", AsyncFutureMode, JaxRsFramework)
+	}
+	return nil
+}
+
 // GenerateJavaServer generates the server code for the RDL-defined service
-func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnnotations bool, genHandlerImpl bool, genUsingPath bool, genParsecError bool, namespace string, isPcSuffix bool) error {
+func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnnotations bool, genHandlerImpl bool, genUsingPath bool, genParsecError bool, namespace string, isPcSuffix bool, jakarta bool, framework string, async string, mergeHandlerImpl bool, genValueTypes bool) error {
+	if err := checkModes(framework, async); err != nil {
+		return err
+	}
 	reg := rdl.NewTypeRegistry(schema)
 	packageDir, err := utils.JavaGenerationDir(outdir, schema, namespace)
 	if err != nil {
@@ -123,16 +165,35 @@ func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnn
 	if err != nil {
 		return err
 	}
-	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
+	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
 	gen.processTemplate(javaServerHandlerTemplate)
 	out.Flush()
 	file.Close()
 
+	// in the future mode, the futures carry the results, in a result class only with the output headers
+	waiting := false
 	for _, r := range schema.Resources {
-		if r.Async != nil && *r.Async {
-			javaServerMakeAsyncResultModel(banner, schema, reg, outdir, r, genAnnotations, genUsingPath, namespace, isPcSuffix, ver)
+		if async == AsyncFutureMode {
+			if len(r.Outputs) > 0 {
+				javaServerMakeResultModel(banner, schema, reg, outdir, r, genAnnotations, genUsingPath, namespace, isPcSuffix, ver, jakarta, framework, async, genValueTypes)
+			}
+		} else if r.Async != nil && *r.Async {
+			javaServerMakeAsyncResultModel(banner, schema, reg, outdir, r, genAnnotations, genUsingPath, namespace, isPcSuffix, ver, jakarta, framework, genValueTypes)
+			waiting = true
 		} else if len(r.Outputs) > 0 {
-			javaServerMakeResultModel(banner, schema, reg, outdir, r, genAnnotations, genUsingPath, namespace, isPcSuffix, ver)
+			javaServerMakeResultModel(banner, schema, reg, outdir, r, genAnnotations, genUsingPath, namespace, isPcSuffix, ver, jakarta, framework, async, genValueTypes)
+		}
+	}
+
+	//WaitRegistry interface and its default for the async result classes
+	if waiting {
+		gen = &javaServerGenerator{reg, schema, cName, nil, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		err = gen.generateFiles(packageDir, []javaServerFile{
+			{"WaitRegistry", javaServerWaitRegistryTemplate},
+			{"InMemoryWaitRegistry", javaServerInMemoryWaitRegistryTemplate},
+		})
+		if err != nil {
+			return err
 		}
 	}
 
@@ -144,36 +205,59 @@ func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnn
 			return err
 		}
 
-		// do nothing if file has already existed
+		gen = &javaServerGenerator{reg, schema, cName, nil, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		packageName := utils.JavaGenerationPackage(schema, namespace)
+
+		ver, err = utils.GetSchemaVersionOrDefault(schema, 1)
+		checkErr(err)
+		// import user defined struct classes
+		for _, t := range schema.Types {
+			tName, tType, _ := rdl.TypeInfo(t)
+			if strings.ToLower(string(tType)) == "struct" || strings.ToLower(string(tType)) == "enum" || strings.ToLower(string(tType)) == "union" || (genValueTypes && utils.IsJavaValueType(reg, t)) {
+				importClass := packageName + "." + string(tName)
+				if ver > 1 {
+					importClass += "V" + strconv.Itoa(int(ver))
+				}
+				if isPcSuffix {
+					importClass += utils.JavaParsecClassSuffix
+				}
+				gen.appendImportClass(importClass)
+			}
+		}
+		gen.appendImportClass(packageName + ".Authenticator")
+		gen.appendImportClass(packageName + ".Authorizer")
+		gen.appendImportClass(packageName + ".DefaultResourceContext")
+		gen.appendImportClass(packageName + ".ResourceContext")
+		gen.appendImportClass(packageName + "." + cName + "Handler")
+		if async == AsyncFutureMode {
+			for _, r := range schema.Resources {
+				if len(r.Outputs) > 0 {
+					gen.appendImportClass(packageName + "." + gen.futureType(r))
+				}
+			}
+			gen.appendImportClass("java.util.concurrent.CompletableFuture")
+		}
+
+		// do nothing if file has already existed, unless asked to merge the new resources into it
 		_, filePath := utils.GetOutputPathInfo(packageSrcDir, cName, "HandlerImpl.java")
 		if _, err := os.Stat(filePath); err == nil {
-			fmt.Fprintln(os.Stderr, "Warning: interface implementation class exists, ignore: ", filePath)
+			if !mergeHandlerImpl {
+				fmt.Fprintln(os.Stderr, "Warning: interface implementation class exists, ignore: ", filePath)
+			} else {
+				warnings, err := gen.mergeHandlerImpl(filePath)
+				if err != nil {
+					return err
+				}
+				for _, warning := range warnings {
+					fmt.Fprintln(os.Stderr, "Warning:", warning)
+				}
+			}
 		} else {
 			out, file, _, err = utils.OutputWriter(packageSrcDir, cName, "HandlerImpl.java")
 			if err != nil {
 				return err
 			}
-			gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
-			packageName := utils.JavaGenerationPackage(schema, namespace)
-
-			ver, err = utils.GetSchemaVersionOrDefault(schema, 1)
-			checkErr(err)
-			// import user defined struct classes
-			for _, t := range schema.Types {
-				tName, tType, _ := rdl.TypeInfo(t)
-				if strings.ToLower(string(tType)) == "struct" || strings.ToLower(string(tType)) == "enum" {
-					importClass := packageName + "." + string(tName)
-					if ver > 1 {
-						importClass += "V" + strconv.Itoa(int(ver))
-					}
-					if isPcSuffix {
-						importClass += utils.JavaParsecClassSuffix
-					}
-					gen.appendImportClass(importClass)
-				}
-			}
-			gen.appendImportClass(packageName + ".ResourceContext")
-			gen.appendImportClass(packageName + "." + cName + "Handler")
+			gen.writer = out
 			gen.processTemplate(javaServerHandlerImplTemplate)
 			out.Flush()
 			file.Close()
@@ -186,7 +270,7 @@ func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnn
 	if err != nil {
 		return err
 	}
-	gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
+	gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
 	gen.processTemplate(javaServerContextTemplate)
 	out.Flush()
 	file.Close()
@@ -194,40 +278,79 @@ func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnn
 		return gen.err
 	}
 
-	//FooResources Jax-RS glue
-	out, file, _, err = utils.OutputWriter(packageDir, cName, "Resources.java")
+	//DefaultResourceContext class and the Authenticator and Authorizer it delegates to
+	gen = &javaServerGenerator{reg, schema, cName, nil, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+	err = gen.generateFiles(packageDir, []javaServerFile{
+		{"DefaultResourceContext", javaServerDefaultContextTemplate},
+		{"Authenticator", javaServerAuthenticatorTemplate},
+		{"Authorizer", javaServerAuthorizerTemplate},
+	})
 	if err != nil {
 		return err
 	}
-	gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
-	for _, r := range schema.Resources {
-		gen.generateImportClass(r)
-	}
-	sort.Strings(gen.imports)
-	gen.processTemplate(javaServerTemplate)
-	out.Flush()
-	file.Close()
-	if gen.err != nil {
-		return gen.err
-	}
 
-	//Note: to enable jackson's pretty printer:
-	//import com.fasterxml.jackson.jaxrs.annotation.JacksonFeatures;
-	//import com.fasterxml.jackson.databind.SerializationFeature;
-	//for each resource, add this annotation:
-	//   @JacksonFeatures(serializationEnable =  { SerializationFeature.INDENT_OUTPUT })
+	if framework == SpringFramework {
+		//FooController Spring MVC glue
+		out, file, _, err = utils.OutputWriter(packageDir, cName, "Controller.java")
+		if err != nil {
+			return err
+		}
+		gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		for _, r := range schema.Resources {
+			gen.generateImportClass(r)
+		}
+		sort.Strings(gen.imports)
+		gen.processTemplate(javaServerSpringTemplate)
+		out.Flush()
+		file.Close()
+		if gen.err != nil {
+			return gen.err
+		}
 
-	//FooServer - an optional server wrapper that sets up Jetty9/Jersey2 to run Foo
-	out, file, _, err = utils.OutputWriter(packageDir, cName, "Server.java")
-	if err != nil {
-		return err
-	}
-	gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
-	gen.processTemplate(javaServerInitTemplate)
-	out.Flush()
-	file.Close()
-	if gen.err != nil {
-		return gen.err
+		//FooControllerAdvice - the responses of the resource exceptions the controller does not catch
+		gen = &javaServerGenerator{reg, schema, cName, nil, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		err = gen.generateFiles(packageDir, []javaServerFile{
+			{cName + "ControllerAdvice", javaServerControllerAdviceTemplate},
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		//FooResources Jax-RS glue
+		out, file, _, err = utils.OutputWriter(packageDir, cName, "Resources.java")
+		if err != nil {
+			return err
+		}
+		gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		for _, r := range schema.Resources {
+			gen.generateImportClass(r)
+		}
+		sort.Strings(gen.imports)
+		gen.processTemplate(javaServerTemplate)
+		out.Flush()
+		file.Close()
+		if gen.err != nil {
+			return gen.err
+		}
+
+		//Note: to enable jackson's pretty printer:
+		//import com.fasterxml.jackson.jaxrs.annotation.JacksonFeatures;
+		//import com.fasterxml.jackson.databind.SerializationFeature;
+		//for each resource, add this annotation:
+		//   @JacksonFeatures(serializationEnable =  { SerializationFeature.INDENT_OUTPUT })
+
+		//FooServer - an optional server wrapper that sets up Jetty9/Jersey2 to run Foo
+		out, file, _, err = utils.OutputWriter(packageDir, cName, "Server.java")
+		if err != nil {
+			return err
+		}
+		gen = &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+		gen.processTemplate(javaServerInitTemplate)
+		out.Flush()
+		file.Close()
+		if gen.err != nil {
+			return gen.err
+		}
 	}
 
 	//ResourceException - the throawable wrapper for alternate return types
@@ -283,77 +406,107 @@ func GenerateJavaServer(banner string, schema *rdl.Schema, outdir string, genAnn
 		err = utils.JavaGenerateParsecErrorDetail(schema, out, namespace)
 		out.Flush()
 		file.Close()
+
+		if framework != SpringFramework {
+			//ExceptionMappers - the parsec errors for the validation and the resource exceptions
+			gen = &javaServerGenerator{reg, schema, cName, nil, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, genParsecError, genValueTypes}
+			err = gen.generateFiles(packageDir, []javaServerFile{
+				{"ConstraintViolationExceptionMapper", javaServerConstraintViolationMapperTemplate},
+				{"ResourceExceptionMapper", javaServerResourceExceptionMapperTemplate},
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return err
 }
 
-func javaServerMakeAsyncResultModel(banner string, schema *rdl.Schema, reg rdl.TypeRegistry, outdir string, r *rdl.Resource, genAnnotations bool, genUsingPath bool, namespace string, isPcSuffix bool, apiVer int32) error {
+func javaServerMakeAsyncResultModel(banner string, schema *rdl.Schema, reg rdl.TypeRegistry, outdir string, r *rdl.Resource, genAnnotations bool, genUsingPath bool, namespace string, isPcSuffix bool, apiVer int32, jakarta bool, framework string, genValueTypes bool) error {
 	cName := utils.Capitalize(string(r.Type))
 	packageDir, err := utils.JavaGenerationDir(outdir, schema, namespace)
 	if err != nil {
 		return err
 	}
-	methName, _ := javaMethodName(reg, r, genUsingPath, isPcSuffix, apiVer)
+	methName, _ := javaMethodName(reg, r, genUsingPath, isPcSuffix, apiVer, genValueTypes)
 	s := utils.Capitalize(methName) + "Result"
 	out, file, _, err := utils.OutputWriter(packageDir, s, ".java")
 	if err != nil {
 		return err
 	}
-	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
+	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, AsyncResultMode, false, genValueTypes}
 	funcMap := template.FuncMap{
-		"header":           func() string { return utils.JavaGenerationHeader(gen.banner) },
-		"package":          func() string { return utils.JavaGenerationPackage(gen.schema, namespace) },
-		"openBrace":        func() string { return "{" },
-		"name":             func() string { return utils.Uncapitalize(string(r.Type)) },
-		"cName":            func() string { return utils.Capitalize(string(r.Type)) },
-		"resultArgs":       func() string { return gen.resultArgs(r) },
-		"resultSig":        func() string { return gen.resultSignature(r) },
-		"rName":            func() string { return s },
-		"pathParamsKey":    func() string { return gen.makePathParamsKey(r) },
-		"pathParamsDecls":  func() string { return gen.makePathParamsDecls(r) },
-		"pathParamsSig":    func() []string { return gen.makePathParamsSig(r) },
-		"pathParamsAssign": func() string { return gen.makePathParamsAssign(r) },
-		"headerParamsSig":  func() []string { return gen.makeHeaderParamsSig(r) },
-		"headerAssign":     func() string { return gen.makeHeaderAssign(r) },
-	}
-	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(javaServerAsyncResultTemplate))
+		"header":          func() string { return utils.JavaGenerationHeader(gen.banner) },
+		"package":         func() string { return utils.JavaGenerationPackage(gen.schema, namespace) },
+		"javaee":          func() string { return utils.JavaEENamespace(gen.jakarta) },
+		"openBrace":       func() string { return "{" },
+		"name":            func() string { return utils.Uncapitalize(string(r.Type)) },
+		"cName":           func() string { return utils.Capitalize(string(r.Type)) },
+		"resultArgs":      func() string { return gen.resultArgs(r) },
+		"resultCasts":     func() string { return gen.resultCasts(r) },
+		"resultSig":       func() string { return gen.resultSignature(r) },
+		"rName":           func() string { return s },
+		"waitKey":         func() string { return gen.makeWaitKey(r) },
+		"waitKeyDecls":    func() string { return gen.makeWaitKeyDecls(r) },
+		"waitKeySig":      func() []string { return gen.makeWaitKeySig(r) },
+		"waitKeyAssign":   func() string { return gen.makeWaitKeyAssign(r) },
+		"headerParamsSig": func() []string { return gen.makeHeaderParamsSig(r) },
+		"headerAssign":    func() string { return gen.makeHeaderAssign(r) },
+		"async":           func() bool { return true },
+	}
+	templateSource := javaServerAsyncResultTemplate
+	if framework == SpringFramework {
+		templateSource = javaServerSpringResultTemplate
+	}
+	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(templateSource))
 	err = t.Execute(gen.writer, gen.schema)
 	out.Flush()
 	file.Close()
 	return err
 }
 
-func javaServerMakeResultModel(banner string, schema *rdl.Schema, reg rdl.TypeRegistry, outdir string, r *rdl.Resource, genAnnotations bool, genUsingPath bool, namespace string, isPcSuffix bool, apiVer int32) error {
+func javaServerMakeResultModel(banner string, schema *rdl.Schema, reg rdl.TypeRegistry, outdir string, r *rdl.Resource, genAnnotations bool, genUsingPath bool, namespace string, isPcSuffix bool, apiVer int32, jakarta bool, framework string, async string, genValueTypes bool) error {
 	rType := string(r.Type)
 	cName := utils.Capitalize(rType)
 	packageDir, err := utils.JavaGenerationDir(outdir, schema, namespace)
 	if err != nil {
 		return err
 	}
-	methName, _ := javaMethodName(reg, r, genUsingPath, isPcSuffix, apiVer)
+	methName, _ := javaMethodName(reg, r, genUsingPath, isPcSuffix, apiVer, genValueTypes)
 	s := utils.Capitalize(methName) + "Result"
 	out, file, _, err := utils.OutputWriter(packageDir, s, ".java")
 	if err != nil {
 		return err
 	}
-	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix}
+	gen := &javaServerGenerator{reg, schema, cName, out, nil, banner, genAnnotations, nil, genUsingPath, namespace, isPcSuffix, jakarta, framework, async, false, genValueTypes}
 	funcMap := template.FuncMap{
-		"header":           func() string { return utils.JavaGenerationHeader(gen.banner) },
-		"package":          func() string { return utils.JavaGenerationPackage(gen.schema, namespace) },
-		"openBrace":        func() string { return "{" },
-		"name":             func() string { return utils.Uncapitalize(rType) },
-		"cName":            func() string { return utils.Capitalize(rType) },
-		"resultArgs":       func() string { return gen.resultArgs(r) },
-		"resultSig":        func() string { return gen.resultSignature(r) },
-		"rName":            func() string { return utils.Capitalize(strings.ToLower(r.Method)) + rType + "Result" },
-		"pathParamsDecls":  func() string { return gen.makePathParamsDecls(r) },
-		"pathParamsSig":    func() []string { return gen.makePathParamsSig(r) },
-		"pathParamsAssign": func() string { return gen.makePathParamsAssign(r) },
-		"headerParamsSig":  func() []string { return gen.makeHeaderParamsSig(r) },
-		"headerAssign":     func() string { return gen.makeHeaderAssign(r) },
-	}
-	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(javaServerResultTemplate))
+		"header":          func() string { return utils.JavaGenerationHeader(gen.banner) },
+		"package":         func() string { return utils.JavaGenerationPackage(gen.schema, namespace) },
+		"javaee":          func() string { return utils.JavaEENamespace(gen.jakarta) },
+		"openBrace":       func() string { return "{" },
+		"name":            func() string { return utils.Uncapitalize(rType) },
+		"cName":           func() string { return utils.Capitalize(rType) },
+		"resultArgs":      func() string { return gen.resultArgs(r) },
+		"resultCasts":     func() string { return gen.resultCasts(r) },
+		"resultSig":       func() string { return gen.resultSignature(r) },
+		"rName":           func() string { return s },
+		"waitKey":         func() string { return gen.makeWaitKey(r) },
+		"waitKeyDecls":    func() string { return gen.makeWaitKeyDecls(r) },
+		"waitKeySig":      func() []string { return gen.makeWaitKeySig(r) },
+		"waitKeyAssign":   func() string { return gen.makeWaitKeyAssign(r) },
+		"headerParamsSig": func() []string { return gen.makeHeaderParamsSig(r) },
+		"headerAssign":    func() string { return gen.makeHeaderAssign(r) },
+		"async":           func() bool { return false },
+		"fields":          func() []javaResultField { return gen.futureResultFields(r) },
+	}
+	templateSource := javaServerResultTemplate
+	if async == AsyncFutureMode {
+		templateSource = javaServerFutureResultTemplate
+	} else if framework == SpringFramework {
+		templateSource = javaServerSpringResultTemplate
+	}
+	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(templateSource))
 	err = t.Execute(gen.writer, gen.schema)
 	out.Flush()
 	file.Close()
@@ -378,66 +531,86 @@ func (gen *javaServerGenerator) resultArgs(r *rdl.Resource) string {
 
 }
 
-func (gen *javaServerGenerator) makePathParamsKey(r *rdl.Resource) string {
-	var s bytes.Buffer
-	s.WriteString("\"\"")
-	if len(r.Outputs) > 0 || (r.Async != nil && *r.Async) {
+// resultCasts casts the outputs of a result notified through the WaitRegistry back to their types.
+func (gen *javaServerGenerator) resultCasts(r *rdl.Resource) string {
+	s := ""
+	for i, out := range r.Outputs {
+		s += fmt.Sprintf(", (%s) _result[%d]", gen.javaType(gen.registry, out.Type, false, "", ""), i+1)
+	}
+	return s
+}
+
+// waitKeyInputs returns the inputs the requests waiting on an async resource are keyed by: those listed by its
+// x_wait_key annotation, path, query or header params, or else its path params.
+func (gen *javaServerGenerator) waitKeyInputs(r *rdl.Resource) []*rdl.ResourceInput {
+	var inputs []*rdl.ResourceInput
+	if len(r.Outputs) == 0 && (r.Async == nil || !*r.Async) {
+		return inputs
+	}
+	names := r.Annotations[WaitKeyAnnotationKey]
+	if names == "" {
 		for _, in := range r.Inputs {
 			if in.PathParam {
-				s.WriteString("+" + javaName(in.Name))
+				inputs = append(inputs, in)
 			}
 		}
+		return inputs
 	}
-	return s.String()
-}
-
-func (gen *javaServerGenerator) makePathParamsDecls(r *rdl.Resource) string {
-	s := ""
-	if len(r.Outputs) > 0 || (r.Async != nil && *r.Async) {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		found := false
 		for _, in := range r.Inputs {
-			if in.PathParam {
-				jtype := gen.javaType(gen.registry, in.Type, false, "", "")
-				s += "\n    private " + jtype + " " + javaName(in.Name) + ";"
+			if string(in.Name) == name && (in.PathParam || in.QueryParam != "" || in.Header != "") {
+				inputs = append(inputs, in)
+				found = true
 			}
 		}
+		if !found {
+			log.Println("*** x_wait_key input is not a path, query or header param:", name, "in resource", r)
+		}
+	}
+	return inputs
+}
+
+func (gen *javaServerGenerator) makeWaitKey(r *rdl.Resource) string {
+	var names []string
+	for _, in := range gen.waitKeyInputs(r) {
+		names = append(names, javaName(in.Name))
+	}
+	return "WaitRegistry.key(" + strings.Join(names, ", ") + ")"
+}
+
+func (gen *javaServerGenerator) makeWaitKeyDecls(r *rdl.Resource) string {
+	s := ""
+	for _, in := range gen.waitKeyInputs(r) {
+		jtype := gen.javaType(gen.registry, in.Type, false, "", "")
+		s += "\n    private " + jtype + " " + javaName(in.Name) + ";"
 	}
 	return s
 }
 
-func (gen *javaServerGenerator) makePathParamsSig(r *rdl.Resource) []string {
+func (gen *javaServerGenerator) makeWaitKeySig(r *rdl.Resource) []string {
 	s := make([]string, 0)
-	if len(r.Outputs) > 0 || (r.Async != nil && *r.Async) {
-		for _, in := range r.Inputs {
-			if in.PathParam {
-				jtype := gen.javaType(gen.registry, in.Type, false, "", "")
-				s = append(s, jtype+" "+javaName(in.Name))
-			}
-		}
+	for _, in := range gen.waitKeyInputs(r) {
+		jtype := gen.javaType(gen.registry, in.Type, false, "", "")
+		s = append(s, jtype+" "+javaName(in.Name))
 	}
 	return s
 }
 
-func (gen *javaServerGenerator) makePathParamsArgs(r *rdl.Resource) []string {
+func (gen *javaServerGenerator) makeWaitKeyArgs(r *rdl.Resource) []string {
 	s := make([]string, 0)
-	if len(r.Outputs) > 0 || (r.Async != nil && *r.Async) {
-		for _, in := range r.Inputs {
-			if in.PathParam {
-				s = append(s, javaName(in.Name))
-			}
-		}
+	for _, in := range gen.waitKeyInputs(r) {
+		s = append(s, javaName(in.Name))
 	}
 	return s
 }
 
-func (gen *javaServerGenerator) makePathParamsAssign(r *rdl.Resource) string {
+func (gen *javaServerGenerator) makeWaitKeyAssign(r *rdl.Resource) string {
 	s := ""
-	if len(r.Outputs) > 0 || (r.Async != nil && *r.Async) {
-		for _, in := range r.Inputs {
-			if in.PathParam {
-				jname := javaName(in.Name)
-				s += "\n        this." + jname + " = " + jname + ";"
-			}
-		}
+	for _, in := range gen.waitKeyInputs(r) {
+		jname := javaName(in.Name)
+		s += "\n        this." + jname + " = " + jname + ";"
 	}
 	return s
 }
@@ -458,7 +631,11 @@ func (gen *javaServerGenerator) makeHeaderAssign(r *rdl.Resource) string {
 		for _, out := range r.Outputs {
 			jname := javaName(out.Name)
 			//.header("ETag", revision)
-			s += fmt.Sprintf("\n            .header(%q, %s)", out.Header, jname)
+			if gen.framework == SpringFramework {
+				s += fmt.Sprintf("\n            .header(%q, String.valueOf(%s))", out.Header, jname)
+			} else {
+				s += fmt.Sprintf("\n            .header(%q, %s)", out.Header, jname)
+			}
 		}
 	}
 	return s
@@ -467,10 +644,11 @@ func (gen *javaServerGenerator) makeHeaderAssign(r *rdl.Resource) string {
 const javaServerHandlerTemplate = `{{header}}
 package {{package}};
 
-import javax.servlet.http.HttpServletRequest;
-import javax.servlet.http.HttpServletResponse;
-import javax.ws.rs.container.AsyncResponse;
-import java.util.List;
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;{{if jaxrs}}
+import {{javaee}}.ws.rs.container.AsyncResponse;{{end}}
+import java.util.List;{{if future}}
+import java.util.concurrent.CompletableFuture;{{end}}
 
 //
 // {{cName}}Handler is the interface that the service implementation must implement
@@ -483,23 +661,39 @@ public interface {{cName}}Handler {{openBrace}} {{range .Resources}}
 const javaServerHandlerImplTemplate = `{{origHeader}}
 package {{origPackage}};
 
-{{classImports}}
-import javax.servlet.http.HttpServletRequest;
-import javax.servlet.http.HttpServletResponse;
+{{classImports}}{{if jaxrs}}
+import {{javaee}}.inject.Inject;{{end}}
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;{{if not jaxrs}}
+import org.springframework.beans.factory.annotation.Autowired;
+import org.springframework.lang.Nullable;
+import org.springframework.stereotype.Component;{{end}}
 
 /**
  * {{cName}}HandlerImpl is interface implementation that implement {{cName}}Handler interface.
- */
-public class {{cName}}HandlerImpl implements {{cName}}Handler {{openBrace}}{{range .Resources}}
-
-    @Override
-    {{methodSig .}} {
-        return null;
-    }{{end}}
+ */{{if not jaxrs}}
+@Component{{end}}
+public class {{cName}}HandlerImpl implements {{cName}}Handler {
+    private final Authenticator authenticator;
+    private final Authorizer authorizer;
+
+    public {{cName}}HandlerImpl() {
+        this(null, null);
+    }
+{{if jaxrs}}
+    @Inject
+    public {{cName}}HandlerImpl(Authenticator authenticator, Authorizer authorizer) {{openBrace}}{{else}}
+    // Spring passes null for the Authenticator or Authorizer bean the application does not define
+    @Autowired
+    public {{cName}}HandlerImpl(@Nullable Authenticator authenticator, @Nullable Authorizer authorizer) {{openBrace}}{{end}}
+        this.authenticator = authenticator;
+        this.authorizer = authorizer;
+    }{{range .Resources}}
+{{handlerStub .}}{{end}}
 
     @Override
     public ResourceContext newResourceContext(HttpServletRequest request, HttpServletResponse response) {
-        return new DefaultResourceContext(request, response);
+        return new DefaultResourceContext(request, response, authenticator, authorizer);
     }
 }
 `
@@ -510,11 +704,11 @@ package {{package}};
 import java.util.Collection;
 import java.util.Map;
 import java.util.HashMap;
-import javax.ws.rs.core.Response;
-import javax.ws.rs.WebApplicationException;
+import {{javaee}}.ws.rs.core.Response;
+import {{javaee}}.ws.rs.WebApplicationException;
 
 public final class {{rName}} {
-    private ResourceContext context;{{pathParamsDecls}}
+    private ResourceContext context;{{waitKeyDecls}}
     private int code; //normal result
 
     {{rName}}(ResourceContext context) {
@@ -546,24 +740,21 @@ public final class {{rName}} {
 const javaServerAsyncResultTemplate = `{{header}}
 package {{package}};
 
-import java.util.Collection;
-import java.util.Map;
-import java.util.HashMap;
-import javax.ws.rs.container.AsyncResponse;
-import javax.ws.rs.container.TimeoutHandler;
-import javax.ws.rs.core.Response;
-import javax.ws.rs.WebApplicationException;
+import {{javaee}}.ws.rs.container.AsyncResponse;
+import {{javaee}}.ws.rs.container.TimeoutHandler;
+import {{javaee}}.ws.rs.core.Response;
+import {{javaee}}.ws.rs.WebApplicationException;
 import java.util.concurrent.TimeUnit;
 
-public final class {{rName}} implements TimeoutHandler {
+public final class {{rName}} implements TimeoutHandler, WaitRegistry.Waiter {
     private AsyncResponse _async;
-    private ResourceContext context;{{pathParamsDecls}}
+    private ResourceContext context;{{waitKeyDecls}}
     private int code; //normal result
     private int timeoutCode;
 
-    {{rName}}(ResourceContext context, {{range pathParamsSig}}{{.}}, {{end}}AsyncResponse async) {
+    {{rName}}(ResourceContext context, {{range waitKeySig}}{{.}}, {{end}}AsyncResponse async) {
         this.context = context;
-        this._async = async;{{pathParamsAssign}}
+        this._async = async;{{waitKeyAssign}}
         this.code = 0;
         this.timeoutCode = 0;
     }
@@ -593,60 +784,362 @@ public final class {{rName}} implements TimeoutHandler {
         _async.resume(err);
     }
 
-    private static Map<String,Map<AsyncResponse,{{rName}}>> _waiters = new HashMap<String,Map<AsyncResponse,{{rName}}>>();
-
-    public void wait({{range pathParamsSig}}{{.}}, {{end}}int _timeout, int _normalStatus, int _timeoutStatus) {
+    public void wait({{range waitKeySig}}{{.}}, {{end}}int _timeout, int _normalStatus, int _timeoutStatus) {
         _async.setTimeout(_timeout, TimeUnit.SECONDS);
         this.code = _normalStatus;
         this.timeoutCode = _timeoutStatus;
-        synchronized (_waiters) {
-            Map<AsyncResponse,{{rName}}> m = _waiters.get({{pathParamsKey}});
-            if (m == null) {
-                m = new HashMap<AsyncResponse,{{rName}}>();
-                _waiters.put({{pathParamsKey}}, m);
-            }
-            m.put(_async, this);
-            _async.setTimeoutHandler(this);
-        }
+        _async.setTimeoutHandler(this);
+        WaitRegistry.get().register("{{rName}}", {{waitKey}}, this);
     }
 
     public void handleTimeout(AsyncResponse ar) {
         //the timeout is per-request.
-        {{rName}} result = null;
-        synchronized (_waiters) {
-            Map<AsyncResponse,{{rName}}> m = _waiters.get({{pathParamsKey}});
-            if (m != null) {
-                result = m.remove(ar);
-            }
+        if (WaitRegistry.get().unregister("{{rName}}", {{waitKey}}, this)) {
+            done(timeoutCode);
         }
-        if (result != null) {
-            result.done(timeoutCode);
+    }
+
+    @Override
+    public void resume(Object[] _result) {
+        done(code, ({{cName}}) _result[0]{{resultCasts}});
+    }
+
+    //this get called to notifyAll of changed state
+    public static void notify({{range waitKeySig}}{{.}}, {{end}}{{resultSig}}) {
+        WaitRegistry.get().notify("{{rName}}", {{waitKey}}, new Object[] {{openBrace}}{{resultArgs}}});
+    }
+}
+`
+
+const javaServerFutureResultTemplate = `{{header}}
+package {{package}};
+
+//
+// {{rName}} is what the future of the handler completes with: the entity and the output headers of the response.
+//
+public final class {{rName}} {{openBrace}}{{range fields}}
+    private final {{.Type}} {{.Name}};{{end}}
+
+    public {{rName}}({{range $i, $f := fields}}{{if $i}}, {{end}}{{$f.Type}} {{$f.Name}}{{end}}) {{openBrace}}{{range fields}}
+        this.{{.Name}} = {{.Name}};{{end}}
+    }{{range fields}}
+
+    public {{.Type}} {{.Getter}}() {
+        return {{.Name}};
+    }{{end}}
+}
+`
+
+const javaServerSpringResultTemplate = `{{header}}
+package {{package}};
+
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.context.request.async.DeferredResult;
+
+public final class {{rName}}{{if async}} implements WaitRegistry.Waiter{{end}} {
+    private DeferredResult<ResponseEntity<Object>> _async;
+    private ResourceContext context;{{waitKeyDecls}}
+    private ResponseEntity<Object> response;
+    private int code; //normal result
+    private int timeoutCode;
+
+    {{rName}}(ResourceContext context{{if async}}{{range waitKeySig}}, {{.}}{{end}}{{end}}) {
+        this.context = context;{{if async}}{{waitKeyAssign}}{{end}}
+        this.code = 0;
+        this.timeoutCode = 0;
+    }
+
+    public boolean isAsync() { return {{if async}}true{{else}}false{{end}}; }
+
+    public void done(int _code, {{cName}} {{name}}{{range headerParamsSig}}, {{.}}{{end}}) {
+        ResponseEntity<Object> _resp = ResponseEntity.status(_code){{headerAssign}}
+            .body({{name}});
+        resume(_resp);
+    }
+
+    public void done(int code) {
+        done(code, new ResourceError().code(code).message(ResourceException.codeToString(code)));
+    }
+
+    public void done(int code, Object entity) {
+        this.code = code;
+        //to do: check if the exception is declared, and that the entity is of the declared type
+        resume(ResponseEntity.status(code).body(entity));
+    }
+
+    ResponseEntity<Object> response() {
+        if (response == null) {
+            return ResponseEntity.noContent().build();
+        }
+        return response;
+    }
+
+    private synchronized void resume(ResponseEntity<Object> response) {
+        this.response = response;
+        if (_async != null) {
+            _async.setResult(response);
+        }
+    }{{if async}}
+
+    // the deferred result the controller returns: the waiting one, or one already set to the response
+    synchronized DeferredResult<ResponseEntity<Object>> deferred() {
+        if (_async == null) {
+            _async = new DeferredResult<>();
+            _async.setResult(response());
+        }
+        return _async;
+    }
+
+    public void wait({{range waitKeySig}}{{.}}, {{end}}int _timeout, int _normalStatus, int _timeoutStatus) {
+        synchronized (this) {
+            _async = new DeferredResult<>(_timeout * 1000L);
         }
+        this.code = _normalStatus;
+        this.timeoutCode = _timeoutStatus;
+        _async.onTimeout(this::handleTimeout);
+        WaitRegistry.get().register("{{rName}}", {{waitKey}}, this);
+    }
+
+    private void handleTimeout() {
+        //the timeout is per-request.
+        if (WaitRegistry.get().unregister("{{rName}}", {{waitKey}}, this)) {
+            done(timeoutCode);
+        }
+    }
+
+    @Override
+    public void resume(Object[] _result) {
+        done(code, ({{cName}}) _result[0]{{resultCasts}});
     }
 
     //this get called to notifyAll of changed state
-    public static void notify({{range pathParamsSig}}{{.}}, {{end}}{{resultSig}}) {
-        Collection<{{rName}}> _results = null;
-        synchronized (_waiters) {
-            Map<AsyncResponse,{{rName}}> m = _waiters.remove({{pathParamsKey}});
-            if (m != null) {
-                _results = m.values();
+    public static void notify({{range waitKeySig}}{{.}}, {{end}}{{resultSig}}) {
+        WaitRegistry.get().notify("{{rName}}", {{waitKey}}, new Object[] {{openBrace}}{{resultArgs}}});
+    }{{end}}
+}
+`
+
+const javaServerWaitRegistryTemplate = `{{header}}
+package {{package}};
+
+//
+// WaitRegistry keeps the requests waiting on the async resources until their result is notified, by the name of
+// the result class and a key built from the inputs of the request. The default one, InMemoryWaitRegistry, only
+// reaches the requests of this JVM: set one backed by a message bus to notify those of every server.
+//
+public interface WaitRegistry {
+
+    //
+    // Waiter is a request waiting on an async resource
+    //
+    public interface Waiter {
+        // resume answers the request with the result notified: the resource and then its outputs.
+        public void resume(Object[] result);
+    }
+
+    public void register(String name, String key, Waiter waiter);
+
+    // unregister returns false if the waiter was no longer registered, because it has been notified.
+    public boolean unregister(String name, String key, Waiter waiter);
+
+    // notify resumes and unregisters all the waiters of the key.
+    public void notify(String name, String key, Object[] result);
+
+    public static WaitRegistry get() {
+        return Holder.registry;
+    }
+
+    public static void set(WaitRegistry registry) {
+        Holder.registry = registry;
+    }
+
+    // key joins the inputs, escaping the separator.
+    public static String key(Object... inputs) {
+        StringBuilder key = new StringBuilder();
+        for (int i = 0; i < inputs.length; i++) {
+            if (i > 0) {
+                key.append('/');
             }
+            key.append(String.valueOf(inputs[i]).replace("\\", "\\\\").replace("/", "\\/"));
+        }
+        return key.toString();
+    }
+
+    final class Holder {
+        private static volatile WaitRegistry registry = new InMemoryWaitRegistry();
+    }
+}
+`
+
+const javaServerInMemoryWaitRegistryTemplate = `{{header}}
+package {{package}};
+
+import java.util.HashMap;
+import java.util.HashSet;
+import java.util.Map;
+import java.util.Set;
+
+//
+// InMemoryWaitRegistry keeps the waiting requests of this JVM
+//
+public class InMemoryWaitRegistry implements WaitRegistry {
+    private final Map<String, Set<Waiter>> waiters = new HashMap<>();
+
+    @Override
+    public void register(String name, String key, Waiter waiter) {
+        synchronized (waiters) {
+            waiters.computeIfAbsent(name + "/" + key, k -> new HashSet<>()).add(waiter);
         }
-        if (_results != null) {
-            for ({{rName}} _result : _results) {
-                _result.done(_result.code, {{resultArgs}});
+    }
+
+    @Override
+    public boolean unregister(String name, String key, Waiter waiter) {
+        synchronized (waiters) {
+            Set<Waiter> s = waiters.get(name + "/" + key);
+            if (s == null || !s.remove(waiter)) {
+                return false;
+            }
+            if (s.isEmpty()) {
+                waiters.remove(name + "/" + key);
+            }
+            return true;
+        }
+    }
+
+    @Override
+    public void notify(String name, String key, Object[] result) {
+        Set<Waiter> s;
+        synchronized (waiters) {
+            s = waiters.remove(name + "/" + key);
+        }
+        if (s != null) {
+            for (Waiter waiter : s) {
+                waiter.resume(result);
             }
         }
     }
 }
 `
 
+const javaServerConstraintViolationMapperTemplate = `{{header}}
+package {{package}};
+
+import java.util.ArrayList;
+import java.util.List;
+import {{javaee}}.validation.ConstraintViolation;
+import {{javaee}}.validation.ConstraintViolationException;
+import {{javaee}}.ws.rs.core.MediaType;
+import {{javaee}}.ws.rs.core.Response;
+import {{javaee}}.ws.rs.ext.ExceptionMapper;
+import {{javaee}}.ws.rs.ext.Provider;
+
+//
+// ConstraintViolationExceptionMapper answers the requests that fail the validation with a ParsecResourceError,
+// with one detail per violation.
+//
+@Provider
+public class ConstraintViolationExceptionMapper implements ExceptionMapper<ConstraintViolationException> {
+
+    @Override
+    public Response toResponse(ConstraintViolationException e) {
+        List<ParsecErrorDetail> detail = new ArrayList<>();
+        for (ConstraintViolation<?> violation : e.getConstraintViolations()) {
+            Object invalidValue = violation.getInvalidValue();
+            detail.add(new ParsecErrorDetail()
+                .setMessage(violation.getMessage())
+                .setInvalidValue(invalidValue == null ? null : String.valueOf(invalidValue)));
+        }
+        int code = ResourceException.BAD_REQUEST;
+        ParsecErrorBody body = new ParsecErrorBody()
+            .setCode(code)
+            .setMessage(ResourceException.codeToString(code))
+            .setDetail(detail);
+        return Response.status(code).entity(new ParsecResourceError().setError(body)).type(MediaType.APPLICATION_JSON).build();
+    }
+}
+`
+
+const javaServerResourceExceptionMapperTemplate = `{{header}}
+package {{package}};
+
+import java.util.HashMap;
+import java.util.Map;
+import {{javaee}}.ws.rs.container.ResourceInfo;
+import {{javaee}}.ws.rs.core.Context;
+import {{javaee}}.ws.rs.core.MediaType;
+import {{javaee}}.ws.rs.core.Response;
+import {{javaee}}.ws.rs.ext.ExceptionMapper;
+import {{javaee}}.ws.rs.ext.Provider;
+
+//
+// ResourceExceptionMapper answers the resource exceptions the resources do not handle. A code the resource
+// declares keeps its declared entity, any other code is answered with a ParsecResourceError.
+//
+@Provider
+public class ResourceExceptionMapper implements ExceptionMapper<ResourceException> {
+    private static final Map<String, Map<Integer, Class<?>>> DECLARED = new HashMap<>();
+
+    static {{openBrace}}{{range .Resources}}{{declaredExceptions .}}{{end}}
+    }
+
+    @Context
+    private ResourceInfo resourceInfo;
+
+    private static void declare(String method, int code, Class<?> type) {
+        DECLARED.computeIfAbsent(method, m -> new HashMap<>()).put(code, type);
+    }
+
+    @Override
+    public Response toResponse(ResourceException e) {
+        int code = e.getCode();
+        Object data = e.getData();
+        Map<Integer, Class<?>> declared = null;
+        if (resourceInfo != null && resourceInfo.getResourceMethod() != null) {
+            declared = DECLARED.get(resourceInfo.getResourceMethod().getName());
+        }
+        if (declared != null && declared.containsKey(code) && declared.get(code).isInstance(data)) {
+            return Response.status(code).entity(data).build();
+        }
+        String message = ResourceException.codeToString(code);
+        if (data instanceof ResourceError && ((ResourceError) data).message != null) {
+            message = ((ResourceError) data).message;
+        }
+        ParsecErrorBody body = new ParsecErrorBody().setCode(code).setMessage(message);
+        return Response.status(code).entity(new ParsecResourceError().setError(body)).type(MediaType.APPLICATION_JSON).build();
+    }
+}
+`
+
+const javaServerControllerAdviceTemplate = `{{header}}
+package {{package}};
+
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.ExceptionHandler;
+import org.springframework.web.bind.annotation.RestControllerAdvice;
+
+//
+// {{cName}}ControllerAdvice answers the resource exceptions the controller does not catch: the ones thrown by the
+// authentication, the authorization and the handler methods that take a result.
+//
+@RestControllerAdvice(assignableTypes = {{cName}}Controller.class)
+public class {{cName}}ControllerAdvice {
+
+    @ExceptionHandler(ResourceException.class)
+    public ResponseEntity<Object> handleResourceException(ResourceException e) {
+        Object data = e.getData();
+        if (data == null) {
+            return ResponseEntity.status(e.getCode()).build();
+        }
+        return ResponseEntity.status(e.getCode()).body(data);
+    }
+}
+`
+
 const javaServerContextTemplate = `{{header}}
 package {{package}};
 
-import javax.servlet.http.HttpServletRequest;
-import javax.servlet.http.HttpServletResponse;
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;
 
 //
 // ResourceContext
@@ -659,9 +1152,114 @@ public interface ResourceContext {
 }
 `
 
+const javaServerDefaultContextTemplate = `{{header}}
+package {{package}};
+
+import java.security.Principal;
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;
+
+//
+// DefaultResourceContext checks the credentials of the request with the Authenticator and its access with the
+// Authorizer. Without them, every resource that authenticates or authorizes is refused.
+//
+public class DefaultResourceContext implements ResourceContext {
+    private final HttpServletRequest request;
+    private final HttpServletResponse response;
+    private final Authenticator authenticator;
+    private final Authorizer authorizer;
+    private Principal principal;
+
+    public DefaultResourceContext(HttpServletRequest request, HttpServletResponse response) {
+        this(request, response, null, null);
+    }
+
+    public DefaultResourceContext(HttpServletRequest request, HttpServletResponse response, Authenticator authenticator, Authorizer authorizer) {
+        this.request = request;
+        this.response = response;
+        this.authenticator = authenticator;
+        this.authorizer = authorizer;
+    }
+
+    @Override
+    public HttpServletRequest request() {
+        return request;
+    }
+
+    @Override
+    public HttpServletResponse response() {
+        return response;
+    }
+
+    // principal returns the principal of the request once it is authenticated, null before.
+    public Principal principal() {
+        return principal;
+    }
+
+    @Override
+    public void authenticate() {
+        if (principal != null) {
+            return;
+        }
+        if (authenticator != null) {
+            principal = authenticator.authenticate(request);
+        }
+        if (principal == null) {
+            throw new ResourceException(ResourceException.UNAUTHORIZED);
+        }
+    }
+
+    @Override
+    public void authorize(String action, String resource, String trustedDomain) {
+        authenticate();
+        if (authorizer == null || !authorizer.authorize(action, resource, principal, trustedDomain)) {
+            throw new ResourceException(ResourceException.FORBIDDEN);
+        }
+    }
+}
+`
+
+const javaServerAuthenticatorTemplate = `{{header}}
+package {{package}};
+
+import java.security.Principal;
+import {{javaee}}.servlet.http.HttpServletRequest;
+
+//
+// Authenticator checks the credentials of a request for DefaultResourceContext
+//
+public interface Authenticator {
+    // REJECT_ALL authenticates no request
+    public static final Authenticator REJECT_ALL = request -> null;
+
+    // authenticate returns the principal the credentials of the request stand for, or null if they are missing
+    // or not valid.
+    public Principal authenticate(HttpServletRequest request);
+}
+`
+
+const javaServerAuthorizerTemplate = `{{header}}
+package {{package}};
+
+import java.security.Principal;
+
+//
+// Authorizer checks the access of an authenticated principal for DefaultResourceContext
+//
+public interface Authorizer {
+    // DENY_ALL authorizes no access
+    public static final Authorizer DENY_ALL = (action, resource, principal, trustedDomain) -> false;
+
+    // authorize tells whether the principal may take the action on the resource. The trusted domain is the one of
+    // the authorize spec of the resource, null if it has none.
+    public boolean authorize(String action, String resource, Principal principal, String trustedDomain);
+}
+`
+
 const javaServerInitTemplate = `{{header}}
 package {{package}};
 
+import {{javaee}}.inject.Singleton;
 import org.eclipse.jetty.server.Server;
 import org.eclipse.jetty.servlet.ServletContextHandler;
 import org.eclipse.jetty.servlet.ServletHolder;
@@ -671,45 +1269,140 @@ import org.glassfish.jersey.servlet.ServletContainer;
 
 public class {{cName}}Server {
     {{cName}}Handler handler;
+    Class<? extends {{cName}}Handler> handlerClass;
+    Authenticator authenticator;
+    Authorizer authorizer;
 
     public {{cName}}Server({{cName}}Handler handler) {
         this.handler = handler;
     }
 
-    public void run(int port) {
-        try {
-            Server server = new Server(port);
-            ServletContextHandler handler = new ServletContextHandler();
-            handler.setContextPath("");
-            ResourceConfig config = new ResourceConfig({{cName}}Resources.class).register(new Binder());
-            handler.addServlet(new ServletHolder(new ServletContainer(config)), "/*");
-            server.setHandler(handler);
-            server.start();
-            server.join();
-        } catch (Exception e) {
-            System.err.println("*** " + e);
+    // the handler is created by HK2, which injects the authenticator and the authorizer into it
+    public {{cName}}Server(Class<? extends {{cName}}Handler> handlerClass, Authenticator authenticator, Authorizer authorizer) {
+        this.handlerClass = handlerClass;
+        this.authenticator = authenticator;
+        this.authorizer = authorizer;
+    }
+
+    public void run(int port) {
+        try {
+            Server server = new Server(port);
+            ServletContextHandler handler = new ServletContextHandler();
+            handler.setContextPath("");
+            ResourceConfig config = new ResourceConfig({{cName}}Resources.class).register(new Binder()){{if parsecError}}
+                .register(ConstraintViolationExceptionMapper.class)
+                .register(ResourceExceptionMapper.class){{end}};
+            handler.addServlet(new ServletHolder(new ServletContainer(config)), "/*");
+            server.setHandler(handler);
+            server.start();
+            server.join();
+        } catch (Exception e) {
+            System.err.println("*** " + e);
+        }
+    }
+
+    class Binder extends AbstractBinder {
+        @Override
+        protected void configure() {
+            if (handler != null) {
+                bind(handler).to({{cName}}Handler.class);
+            } else {
+                bind(handlerClass).to({{cName}}Handler.class).in(Singleton.class);
+            }
+            // HK2 needs both to create the handler, the missing ones refuse every resource that needs them
+            bind(authenticator != null ? authenticator : Authenticator.REJECT_ALL).to(Authenticator.class);
+            bind(authorizer != null ? authorizer : Authorizer.DENY_ALL).to(Authorizer.class);
+        }
+    }
+}
+`
+
+const javaServerTemplate = `{{header}}
+package {{package}};
+
+import {{javaee}}.ws.rs.*;
+import {{javaee}}.ws.rs.core.*;
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;
+import {{javaee}}.inject.Inject;
+import {{javaee}}.ws.rs.container.AsyncResponse;
+import {{javaee}}.ws.rs.container.Suspended;
+import java.io.IOException;
+import java.util.Map;
+import java.util.Arrays;
+import java.util.List;
+import java.util.LinkedHashMap;{{if future}}
+import java.util.concurrent.CompletableFuture;
+import java.util.concurrent.CompletionException;
+import java.util.concurrent.TimeUnit;{{end}}
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import com.yahoo.parsec.logging.LogUtil;
+import com.fasterxml.jackson.databind.ObjectMapper;
+{{classImports}}
+
+@Path("{{rootPath}}")
+public class {{cName}}Resources {
+    private static final Logger LOG = LoggerFactory.getLogger({{cName}}Resources.class);
+    private static final ObjectMapper OBJECT_MAPPER = new ObjectMapper();
+{{range .Resources}}
+    @{{uMethod .}}
+    @Path("{{methodPath .}}")
+    {{handlerSig .}} {{openBrace}}
+{{handlerBody .}}    }
+{{end}}
+
+    WebApplicationException typedException(int code, ResourceException e, Class<?> eClass) {
+        Object data = e.getData();
+        Object entity = eClass.isInstance(data) ? data : null;
+        int internalServerErrorCode = ResourceException.INTERNAL_SERVER_ERROR;
+        if ((code == internalServerErrorCode && LOG.isErrorEnabled()) || LOG.isDebugEnabled()) {
+            String msg = object2StringNoThrow(data);
+            String className = this.getClass().getSimpleName();
+            // only log two tiers of stacks, there is no problem even if the range overflow
+            StackTraceElement[] stacks = Arrays.copyOfRange(Thread.currentThread().getStackTrace(), 1, 3);
+            Map<String, String> meta = new LinkedHashMap<>();
+            meta.put("trace_tag", className);
+            meta.put("http_code", String.valueOf(code));
+            meta.put("uri", _request == null ? "" : _request.getRequestURL().toString());
+            meta.put("trace_string", Arrays.toString(stacks));
+            String logInfo = LogUtil.generateLog(className, msg, meta);
+            if (code == internalServerErrorCode) {
+                LOG.error(logInfo);
+            } else {
+                LOG.debug(logInfo);
+            }
+        }
+        if (entity != null)
+            return new WebApplicationException(Response.status(code).entity(entity).build());
+        else
+            return new WebApplicationException(code);
+    }
+
+    private static String object2StringNoThrow(Object entity) {
+        if (entity == null) {
+            return null;
         }
-    }
 
-    class Binder extends AbstractBinder {
-        @Override
-        protected void configure() {
-            bind(handler).to({{cName}}Handler.class);
+        try {
+            return OBJECT_MAPPER.writeValueAsString(entity);
+        } catch (IOException e) {
+            return null;
         }
     }
+
+    @Inject private {{cName}}Handler _delegate;
+    @Context private HttpServletRequest _request;
+    @Context private HttpServletResponse _response;
+
 }
 `
 
-const javaServerTemplate = `{{header}}
+const javaServerSpringTemplate = `{{header}}
 package {{package}};
 
-import javax.ws.rs.*;
-import javax.ws.rs.core.*;
-import javax.servlet.http.HttpServletRequest;
-import javax.servlet.http.HttpServletResponse;
-import javax.inject.Inject;
-import javax.ws.rs.container.AsyncResponse;
-import javax.ws.rs.container.Suspended;
+import {{javaee}}.servlet.http.HttpServletRequest;
+import {{javaee}}.servlet.http.HttpServletResponse;
 import java.io.IOException;
 import java.util.Map;
 import java.util.Arrays;
@@ -717,22 +1410,27 @@ import java.util.List;
 import java.util.LinkedHashMap;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
+import org.springframework.beans.factory.annotation.Autowired;
+import org.springframework.http.ResponseEntity;
+import org.springframework.validation.annotation.Validated;
+import org.springframework.web.bind.annotation.*;
+import org.springframework.web.context.request.async.DeferredResult;
 import com.yahoo.parsec.logging.LogUtil;
 import com.fasterxml.jackson.databind.ObjectMapper;
 {{classImports}}
 
-@Path("{{rootPath}}")
-public class {{cName}}Resources {
-    private static final Logger LOG = LoggerFactory.getLogger({{cName}}Resources.class);
+@RestController
+@Validated
+@RequestMapping("{{rootPath}}")
+public class {{cName}}Controller {
+    private static final Logger LOG = LoggerFactory.getLogger({{cName}}Controller.class);
     private static final ObjectMapper OBJECT_MAPPER = new ObjectMapper();
 {{range .Resources}}
-    @{{uMethod .}}
-    @Path("{{methodPath .}}")
     {{handlerSig .}} {{openBrace}}
 {{handlerBody .}}    }
 {{end}}
 
-    WebApplicationException typedException(int code, ResourceException e, Class<?> eClass) {
+    ResponseEntity<Object> typedResponse(int code, ResourceException e, Class<?> eClass) {
         Object data = e.getData();
         Object entity = eClass.isInstance(data) ? data : null;
         int internalServerErrorCode = ResourceException.INTERNAL_SERVER_ERROR;
@@ -754,9 +1452,9 @@ public class {{cName}}Resources {
             }
         }
         if (entity != null)
-            return new WebApplicationException(Response.status(code).entity(entity).build());
+            return ResponseEntity.status(code).body(entity);
         else
-            return new WebApplicationException(code);
+            return ResponseEntity.status(code).build();
     }
 
     private static String object2StringNoThrow(Object entity) {
@@ -771,9 +1469,9 @@ public class {{cName}}Resources {
         }
     }
 
-    @Inject private {{cName}}Handler _delegate;
-    @Context private HttpServletRequest _request;
-    @Context private HttpServletResponse _response;
+    @Autowired private {{cName}}Handler _delegate;
+    @Autowired private HttpServletRequest _request;
+    @Autowired private HttpServletResponse _response;
 
 }
 `
@@ -807,7 +1505,32 @@ func (gen *javaServerGenerator) makeJavaTypeRef(reg rdl.TypeRegistry, t *rdl.Typ
 func (gen *javaServerGenerator) javaType(reg rdl.TypeRegistry, rdlType rdl.TypeRef, optional bool, items rdl.TypeRef, keys rdl.TypeRef) string {
 	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
 	checkErr(err)
-	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver)
+	return utils.JavaType(reg, rdlType, optional, items, keys, gen.isPcSuffix, ver, gen.genValueTypes)
+}
+
+// javaServerFile is a java file generated from a template into the package directory
+type javaServerFile struct {
+	name           string
+	templateSource string
+}
+
+// generateFiles writes each of the files with a copy of the generator
+func (gen *javaServerGenerator) generateFiles(dir string, files []javaServerFile) error {
+	for _, f := range files {
+		out, file, _, err := utils.OutputWriter(dir, f.name, ".java")
+		if err != nil {
+			return err
+		}
+		fileGen := *gen
+		fileGen.writer = out
+		fileGen.processTemplate(f.templateSource)
+		out.Flush()
+		file.Close()
+		if fileGen.err != nil {
+			return fileGen.err
+		}
+	}
+	return nil
 }
 
 func (gen *javaServerGenerator) processTemplate(templateSource string) error {
@@ -836,6 +1559,10 @@ func (gen *javaServerGenerator) processTemplate(templateSource string) error {
 	funcMap := template.FuncMap{
 		"header":      func() string { return utils.JavaGenerationHeader(gen.banner) },
 		"package":     func() string { return utils.JavaGenerationPackage(gen.schema, gen.namespace) },
+		"javaee":      func() string { return utils.JavaEENamespace(gen.jakarta) },
+		"jaxrs":       func() bool { return gen.framework != SpringFramework },
+		"future":      func() bool { return gen.async == AsyncFutureMode },
+		"parsecError": func() bool { return gen.genParsecError },
 		"openBrace":   func() string { return "{" },
 		"field":       fieldFun,
 		"flattened":   func(t *rdl.Type) []*rdl.StructFieldDef { return utils.FlattenedFields(gen.registry, t) },
@@ -844,6 +1571,7 @@ func (gen *javaServerGenerator) processTemplate(templateSource string) error {
 		"comment":     commentFun,
 		"uMethod":     func(r *rdl.Resource) string { return strings.ToUpper(r.Method) },
 		"methodSig":   func(r *rdl.Resource) string { return gen.serverMethodSignature(r) },
+		"handlerStub": func(r *rdl.Resource) string { return gen.handlerStub(r) },
 		"handlerSig":  func(r *rdl.Resource) string { return gen.handlerSignature(r) },
 		"handlerBody": func(r *rdl.Resource) string { return gen.handlerBody(r) },
 		"client":      func() string { return gen.name + "Client" },
@@ -856,9 +1584,10 @@ func (gen *javaServerGenerator) processTemplate(templateSource string) error {
 		"rName": func(r *rdl.Resource) string {
 			return utils.Capitalize(strings.ToLower(r.Method)) + string(r.Type) + "Result"
 		},
-		"classImports": func() string { return strings.Join(gen.imports, "") },
-		"origPackage":  func() string { return utils.JavaGenerationOrigPackage(gen.schema, gen.namespace) },
-		"origHeader":   func() string { return utils.JavaGenerationOrigHeader(gen.banner) },
+		"classImports":       func() string { return strings.Join(gen.imports, "") },
+		"origPackage":        func() string { return utils.JavaGenerationOrigPackage(gen.schema, gen.namespace) },
+		"origHeader":         func() string { return utils.JavaGenerationOrigHeader(gen.banner) },
+		"declaredExceptions": func(r *rdl.Resource) string { return gen.declaredExceptions(r) },
 	}
 	t := template.Must(template.New(gen.name).Funcs(funcMap).Parse(templateSource))
 	return t.Execute(gen.writer, gen.schema)
@@ -874,12 +1603,22 @@ func (gen *javaServerGenerator) resourcePath(r *rdl.Resource) string {
 }
 
 func (gen *javaServerGenerator) handlerBody(r *rdl.Resource) string {
+	if gen.async == AsyncFutureMode {
+		return gen.futureHandlerBody(r)
+	}
 	async := r.Async != nil && *r.Async
 	resultWrapper := len(r.Outputs) > 0 || async
 	returnType := "void"
 	if !resultWrapper {
 		returnType = gen.javaType(gen.registry, r.Type, false, "", "")
 	}
+	spring := gen.framework == SpringFramework
+	noContentResponse := "Response.noContent().build()"
+	typedError := "throw typedException"
+	if spring {
+		noContentResponse = "ResponseEntity.noContent().build()"
+		typedError = "return typedResponse"
+	}
 	s := ""
 	if resultWrapper {
 		s += "        ResourceContext _context = _delegate.newResourceContext(_request, _response);\n"
@@ -887,11 +1626,154 @@ func (gen *javaServerGenerator) handlerBody(r *rdl.Resource) string {
 		s += "        try {\n"
 		s += "            ResourceContext _context = _delegate.newResourceContext(_request, _response);\n"
 	}
-	var fargs []string
-	bodyName := ""
+	indent := "            "
+	if resultWrapper {
+		indent = "        "
+	}
+	s += gen.handlerChecks(r, indent)
+	fargs := gen.handlerArgs(r)
+	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
+	checkErr(err)
+	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
+	sargs := ""
+	if len(fargs) > 0 {
+		sargs = ", " + strings.Join(fargs, ", ")
+	}
+	if resultWrapper {
+		rName := utils.Capitalize(methName) + "Result"
+		s += "        " + rName + " result = new " + rName + "(_context"
+		if spring && async {
+			for _, arg := range gen.makeWaitKeyArgs(r) {
+				s += ", " + arg
+			}
+		} else if async {
+			s += ", " + strings.Join(append(gen.makeWaitKeyArgs(r), "asyncResp"), ", ")
+		}
+		s += ");\n"
+		sargs += ", result"
+		s += "        _delegate." + methName + "(_context" + sargs + ");\n"
+		if spring && async {
+			s += "        return result.deferred();\n"
+		} else if spring {
+			s += "        return result.response();\n"
+		}
+	} else {
+		noContent := (r.Expected == "NO_CONTENT" && r.Alternatives == nil) || returnType == "Null"
+		s += "            "
+		if !noContent {
+			s += returnType + " e = "
+		}
+		s += "_delegate." + methName + "(_context" + sargs + ");\n"
+		if len(r.Outputs) > 0 {
+			for _, o := range r.Outputs {
+				s += fmt.Sprintf("            _response.addHeader(%q, e.%s);\n", o.Header, o.Name)
+			}
+		}
+		if noContent {
+			s += "            return " + noContentResponse + ";\n"
+		} else {
+			s += "            if (null == e) {\n"
+			s += "                return " + noContentResponse + ";\n"
+			s += "            }\n"
+			if spring {
+				s += "            return ResponseEntity.status(ResourceException." + r.Expected + ").body(e);\n"
+			} else {
+				s += "            return Response.status(ResourceException." + r.Expected + ").entity(e).build();\n"
+			}
+		}
+		s += "        } catch (ResourceException e) {\n"
+		s += "            int _code = e.getCode();\n"
+		resume := func(etype string) string {
+			return typedError + "(_code, e, " + etype + ".class);\n"
+		}
+		undeclared := resume("ResourceError")
+		if gen.genParsecError && !spring {
+			// left to the ResourceExceptionMapper
+			undeclared = "throw e;\n"
+		}
+		s += gen.exceptionSwitch(r, methName, returnType, resume, undeclared)
+		s += "        }\n"
+	}
+	return s
+}
+
+// futureHandlerBody bridges the CompletableFuture returned by the handler to the suspended AsyncResponse.
+func (gen *javaServerGenerator) futureHandlerBody(r *rdl.Resource) string {
+	returnType := gen.javaType(gen.registry, r.Type, false, "", "")
+	futureType := gen.futureType(r)
+	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
+	checkErr(err)
+	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
+	sargs := ""
+	if fargs := gen.handlerArgs(r); len(fargs) > 0 {
+		sargs = ", " + strings.Join(fargs, ", ")
+	}
+	s := ""
+	if timeout := gen.timeoutMillis(r); timeout > 0 {
+		s += fmt.Sprintf("        asyncResp.setTimeout(%d, TimeUnit.MILLISECONDS);\n", timeout)
+	}
+	s += "        CompletableFuture<" + futureType + "> _future;\n"
+	s += "        try {\n"
+	s += "            ResourceContext _context = _delegate.newResourceContext(_request, _response);\n"
+	s += gen.handlerChecks(r, "            ")
+	s += "            _future = _delegate." + methName + "(_context" + sargs + ");\n"
+	s += "        } catch (ResourceException e) {\n"
+	s += "            _future = new CompletableFuture<>();\n"
+	s += "            _future.completeExceptionally(e);\n"
+	s += "        }\n"
+	s += "        _future.whenComplete((_result, _t) -> {\n"
+	s += "            if (_t == null) {\n"
+	if len(r.Outputs) > 0 {
+		resp := "Response.noContent()"
+		fields := gen.futureResultFields(r)
+		if len(fields) > len(r.Outputs) {
+			resp = "Response.status(ResourceException." + r.Expected + ").entity(_result." + fields[0].Getter + "())"
+			fields = fields[1:]
+		}
+		for i, out := range r.Outputs {
+			resp += fmt.Sprintf(".header(%q, _result.%s())", out.Header, fields[i].Getter)
+		}
+		s += "                asyncResp.resume(_result == null ? Response.noContent().build() : " + resp + ".build());\n"
+	} else if futureType == "Void" {
+		s += "                asyncResp.resume(Response.noContent().build());\n"
+	} else {
+		s += "                asyncResp.resume(_result == null ? Response.noContent().build() : Response.status(ResourceException." + r.Expected + ").entity(_result).build());\n"
+	}
+	s += "                return;\n"
+	s += "            }\n"
+	s += "            Throwable _cause = _t instanceof CompletionException && _t.getCause() != null ? _t.getCause() : _t;\n"
+	s += "            if (!(_cause instanceof ResourceException)) {\n"
+	s += "                asyncResp.resume(_cause);\n"
+	s += "                return;\n"
+	s += "            }\n"
+	s += "            ResourceException e = (ResourceException) _cause;\n"
+	s += "            int _code = e.getCode();\n"
+	resume := func(etype string) string {
+		return "asyncResp.resume(typedException(_code, e, " + etype + ".class));\n                break;\n"
+	}
+	undeclared := resume("ResourceError")
+	if gen.genParsecError {
+		// left to the ResourceExceptionMapper
+		undeclared = "asyncResp.resume(e);\n                break;\n"
+	}
+	s += gen.exceptionSwitch(r, methName, returnType, resume, undeclared)
+	s += "        });\n"
+	return s
+}
+
+// handlerChecks returns the statements that run before the handler: the deprecation headers and the auth checks.
+func (gen *javaServerGenerator) handlerChecks(r *rdl.Resource, indent string) string {
+	s := ""
+	if utils.IsDeprecated(r.Annotations) {
+		// advertise the deprecation to the callers (draft-ietf-httpapi-deprecation-header, RFC 8594)
+		s += indent + "_response.setHeader(\"Deprecation\", \"true\");\n"
+		if sunset := r.Annotations[utils.SunsetAnnotationKey]; sunset != "" {
+			s += fmt.Sprintf(indent+"_response.setHeader(\"Sunset\", %q);\n", utils.SunsetHTTPDate(sunset))
+		}
+	}
 	if r.Auth != nil {
 		if r.Auth.Authenticate {
-			s += "            _context.authenticate();\n"
+			s += indent + "_context.authenticate();\n"
 		} else if r.Auth.Action != "" && r.Auth.Resource != "" {
 			resource := r.Auth.Resource
 			i := strings.Index(resource, "{")
@@ -905,18 +1787,32 @@ func (gen *javaServerGenerator) handlerBody(r *rdl.Resource) string {
 				i = strings.Index(resource, "{")
 			}
 			resource = "\"" + resource + "\""
-			s += fmt.Sprintf("            _context.authorize(%q, %s, null);\n", r.Auth.Action, resource)
-			//what about the domain variant?
+			trustedDomain := "null"
+			if r.Auth.Domain != "" {
+				trustedDomain = fmt.Sprintf("%q", r.Auth.Domain)
+			}
+			s += fmt.Sprintf(indent+"_context.authorize(%q, %s, %s);\n", r.Auth.Action, resource, trustedDomain)
 		} else {
 			log.Println("*** Badly formed auth spec in resource input:", r)
 		}
 	}
+	return s
+}
+
+// handlerArgs returns the names of the inputs the handler is called with.
+func (gen *javaServerGenerator) handlerArgs(r *rdl.Resource) []string {
+	var fargs []string
+	bodyName := ""
 	for _, in := range r.Inputs {
 		name := javaName(in.Name)
 		if in.QueryParam != "" {
 			//if !(in.Optional || in.Default != nil) {
 			//	log.Println("RDL error: queryparam must either be optional or have a default value:", in.Name, "in resource", r)
 			//}
+			if gen.isValueType(in.Type) && gen.registry.FindBaseType(in.Type) == rdl.BaseTypeArray {
+				// the resource takes the list of the values of the query param
+				name = name + " == null ? null : new " + gen.javaType(gen.registry, in.Type, true, "", "") + "(" + name + ")"
+			}
 			fargs = append(fargs, name)
 		} else if in.PathParam {
 			fargs = append(fargs, name)
@@ -927,67 +1823,106 @@ func (gen *javaServerGenerator) handlerBody(r *rdl.Resource) string {
 			fargs = append(fargs, bodyName)
 		}
 	}
+	return fargs
+}
+
+// exceptionSwitch returns the switch on the code of the ResourceException e, handled by the statement that resume
+// returns for the type of the error, or by the undeclared statement if the resource does not declare the code.
+func (gen *javaServerGenerator) exceptionSwitch(r *rdl.Resource, methName string, returnType string, resume func(etype string) string, undeclared string) string {
+	s := "            switch (_code) {\n"
+	if len(r.Alternatives) > 0 {
+		for _, alt := range r.Alternatives {
+			s += "            case ResourceException." + alt + ":\n"
+		}
+		s += "                " + resume(returnType)
+	}
+	if r.Exceptions != nil && len(r.Exceptions) > 0 {
+		for ecode, edef := range r.Exceptions {
+			etype := edef.Type
+			s += "            case ResourceException." + ecode + ":\n"
+			s += "                " + resume(etype)
+		}
+	}
+	s += "            default:\n"
+	s += "                System.err.println(\"*** Warning: undeclared exception (\"+_code+\") for resource " + methName + "\");\n"
+	s += "                " + undeclared
+	s += "            }\n"
+	return s
+}
+
+// declaredExceptions returns the statements that declare the exceptions of the resource to the ResourceExceptionMapper.
+func (gen *javaServerGenerator) declaredExceptions(r *rdl.Resource) string {
 	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
 	checkErr(err)
-	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver)
-	sargs := ""
-	if len(fargs) > 0 {
-		sargs = ", " + strings.Join(fargs, ", ")
+	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
+	var codes []string
+	for ecode := range r.Exceptions {
+		codes = append(codes, ecode)
 	}
-	if resultWrapper {
-		rName := utils.Capitalize(methName) + "Result"
-		s += "        " + rName + " result = new " + rName + "(_context"
-		if async {
-			s += ", " + strings.Join(append(gen.makePathParamsArgs(r), "asyncResp"), ", ")
-		}
-		s += ");\n"
-		sargs += ", result"
-		s += "        _delegate." + methName + "(_context" + sargs + ");\n"
-	} else {
-		noContent := (r.Expected == "NO_CONTENT" && r.Alternatives == nil) || returnType == "Null"
-		s += "            "
-		if !noContent {
-			s += returnType + " e = "
-		}
-		s += "_delegate." + methName + "(_context" + sargs + ");\n"
-		if len(r.Outputs) > 0 {
-			for _, o := range r.Outputs {
-				s += fmt.Sprintf("            _response.addHeader(%q, e.%s);\n", o.Header, o.Name)
-			}
-		}
-		if noContent {
-			s += "            return Response.noContent().build();\n"
-		} else {
-			s += "            if (null == e) {\n"
-			s += "                return Response.noContent().build();\n"
-			s += "            }\n"
-			s += "            return Response.status(ResourceException." + r.Expected + ").entity(e).build();\n"
-		}
-		s += "        } catch (ResourceException e) {\n"
-		s += "            int _code = e.getCode();\n"
-		s += "            switch (_code) {\n"
-		if len(r.Alternatives) > 0 {
-			for _, alt := range r.Alternatives {
-				s += "            case ResourceException." + alt + ":\n"
-			}
-			s += "                throw typedException(_code, e, " + returnType + ".class);\n"
-		}
-		if r.Exceptions != nil && len(r.Exceptions) > 0 {
-			for ecode, edef := range r.Exceptions {
-				etype := edef.Type
-				s += "            case ResourceException." + ecode + ":\n"
-				s += "                throw typedException(_code, e, " + etype + ".class);\n"
-			}
-		}
-		s += "            default:\n"
-		s += "                System.err.println(\"*** Warning: undeclared exception (\"+_code+\") for resource " + methName + "\");\n"
-		s += "                throw typedException(_code, e, ResourceError.class);\n" //? really
-		s += "            }\n"
-		s += "        }\n"
+	sort.Strings(codes)
+	s := ""
+	for _, ecode := range codes {
+		s += fmt.Sprintf("\n        declare(%q, ResourceException.%s, %s.class);", methName, ecode, r.Exceptions[ecode].Type)
 	}
 	return s
 }
 
+// futureType returns the type of the CompletableFuture a handler returns in the future mode.
+func (gen *javaServerGenerator) futureType(r *rdl.Resource) string {
+	if len(r.Outputs) > 0 {
+		ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
+		checkErr(err)
+		methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
+		return utils.Capitalize(methName) + "Result"
+	}
+	returnType := gen.javaType(gen.registry, r.Type, true, "", "")
+	if (r.Expected == "NO_CONTENT" && r.Alternatives == nil) || returnType == "Null" {
+		return "Void"
+	}
+	return returnType
+}
+
+// javaResultField is a field of a result class, with its getter
+type javaResultField struct {
+	Type   string
+	Name   string
+	Getter string
+}
+
+// futureResultFields returns the fields of the result class of a resource with outputs in the future mode: the
+// entity, unless the resource has no content, and the output headers.
+func (gen *javaServerGenerator) futureResultFields(r *rdl.Resource) []javaResultField {
+	var fields []javaResultField
+	entityType := gen.javaType(gen.registry, r.Type, true, "", "")
+	if !((r.Expected == "NO_CONTENT" && r.Alternatives == nil) || entityType == "Null") {
+		name := utils.Uncapitalize(string(r.Type))
+		fields = append(fields, javaResultField{entityType, name, "get" + utils.Capitalize(name)})
+	}
+	for _, out := range r.Outputs {
+		name := javaName(out.Name)
+		fields = append(fields, javaResultField{gen.javaType(gen.registry, out.Type, true, "", ""), name, "get" + utils.Capitalize(string(out.Name))})
+	}
+	return fields
+}
+
+// timeoutMillis returns the timeout of the x_timeout annotation of the resource, a duration like "30s" or a number
+// of seconds, 0 if it has none.
+func (gen *javaServerGenerator) timeoutMillis(r *rdl.Resource) int64 {
+	value := r.Annotations[TimeoutAnnotationKey]
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return int64(seconds) * 1000
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Println("*** Badly formed x_timeout in resource:", r)
+		return 0
+	}
+	return int64(d / time.Millisecond)
+}
+
 func (gen *javaServerGenerator) paramInit(qname string, pname string, ptype rdl.TypeRef, pdefault *interface{}) string {
 	reg := gen.registry
 	s := ""
@@ -1025,12 +1960,20 @@ func (gen *javaServerGenerator) paramInit(qname string, pname string, ptype rdl.
 func (gen *javaServerGenerator) handlerSignature(r *rdl.Resource) string {
 	//returnType := utils.JavaType(gen.registry, r.Type, false, "", "")
 	returnType := "Response"
+	spring := gen.framework == SpringFramework
+	if spring {
+		returnType = "ResponseEntity<Object>"
+	}
 	reg := gen.registry
 	var params []string
-	if r.Async != nil && *r.Async {
-		params = append(params, "@Suspended AsyncResponse asyncResp")
-		returnType = "void"
-	} else if len(r.Outputs) > 0 {
+	if (r.Async != nil && *r.Async) || gen.async == AsyncFutureMode {
+		if spring {
+			returnType = "DeferredResult<ResponseEntity<Object>>"
+		} else {
+			params = append(params, "@Suspended AsyncResponse asyncResp")
+			returnType = "void"
+		}
+	} else if len(r.Outputs) > 0 && !spring {
 		returnType = "void"
 	}
 	for _, v := range r.Inputs {
@@ -1040,10 +1983,18 @@ func (gen *javaServerGenerator) handlerSignature(r *rdl.Resource) string {
 		}
 		k := v.Name
 		pdecl := ""
-		if len(v.Annotations) == 0 {
-			v.Annotations = utils.GetUserDefinedTypeAnnotations(v.Type, gen.schema.Types)
-		}
-		if v.QueryParam != "" {
+		v.Annotations = gen.constraintAnnotations(v.Type, v.Annotations)
+		if spring {
+			if v.QueryParam != "" {
+				pdecl = gen.extendedValueAnnotation(v.Annotations) + springParamAnnotation("@RequestParam", v.QueryParam, v.Default)
+			} else if v.PathParam {
+				pdecl = gen.extendedValueAnnotation(v.Annotations) + fmt.Sprintf("@PathVariable(%q) ", k)
+			} else if v.Header != "" {
+				pdecl = gen.extendedValueAnnotation(v.Annotations) + springParamAnnotation("@RequestHeader", v.Header, v.Default)
+			} else {
+				pdecl = gen.extendedValueAnnotation(v.Annotations) + "@RequestBody "
+			}
+		} else if v.QueryParam != "" {
 			pdecl = gen.extendedValueAnnotation(v.Annotations) + fmt.Sprintf("@QueryParam(%q) ", v.QueryParam) + defaultValueAnnotation(v.Default)
 		} else if v.PathParam {
 			pdecl = gen.extendedValueAnnotation(v.Annotations) + fmt.Sprintf("@PathParam(%q) ", k)
@@ -1063,49 +2014,83 @@ func (gen *javaServerGenerator) handlerSignature(r *rdl.Resource) string {
 		params = append(params, "\n        "+pdecl+ptype+" "+javaName(k))
 	}
 	spec := ""
+	if spring {
+		spec = gen.springRequestMapping(r)
+	} else {
+		if len(r.Produces) > 0 {
+			spec += "@Produces({\"" + strings.Join(r.Produces, ", ") + "\"})\n"
+		} else {
+			spec += "@Produces(\"application/json;charset=utf-8\")\n"
+		}
+		switch r.Method {
+		case "POST", "PUT":
+			if len(r.Consumes) > 0 {
+				spec += "    @Consumes({\"" + strings.Join(r.Consumes, ", ") + "\"})\n"
+			} else {
+				spec += "    @Consumes(\"application/json;charset=utf-8\")\n"
+			}
+		}
+	}
+	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
+	checkErr(err)
+	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
+	return spec + "    public " + returnType + " " + methName + "(" + strings.Join(params, ", ") + "\n    )"
+}
+
+// springRequestMapping returns the Spring MVC mapping annotation of the resource
+func (gen *javaServerGenerator) springRequestMapping(r *rdl.Resource) string {
+	mapping := ""
+	switch r.Method {
+	case "GET", "POST", "PUT", "DELETE", "PATCH":
+		mapping = "@" + utils.Capitalize(strings.ToLower(r.Method)) + "Mapping("
+	default:
+		mapping = "@RequestMapping(method = RequestMethod." + strings.ToUpper(r.Method) + ", "
+	}
+	mapping += fmt.Sprintf("value = %q", gen.resourcePath(r))
 	if len(r.Produces) > 0 {
-		spec += "@Produces({\"" + strings.Join(r.Produces, ", ") + "\"})\n"
+		mapping += ", produces = {\"" + strings.Join(r.Produces, "\", \"") + "\"}"
 	} else {
-		spec += "@Produces(\"application/json;charset=utf-8\")\n"
+		mapping += ", produces = \"application/json;charset=utf-8\""
 	}
 	switch r.Method {
 	case "POST", "PUT":
 		if len(r.Consumes) > 0 {
-			spec += "    @Consumes({\"" + strings.Join(r.Consumes, ", ") + "\"})\n"
+			mapping += ", consumes = {\"" + strings.Join(r.Consumes, "\", \"") + "\"}"
 		} else {
-			spec += "    @Consumes(\"application/json;charset=utf-8\")\n"
+			mapping += ", consumes = \"application/json;charset=utf-8\""
 		}
 	}
-	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
-	checkErr(err)
-	methName, _ := javaMethodName(gen.registry, r, gen.genUsingPath, gen.isPcSuffix, ver)
-	return spec + "    public " + returnType + " " + methName + "(" + strings.Join(params, ", ") + "\n    )"
+	return mapping + ")\n"
+}
+
+func springParamAnnotation(annotation string, name string, val interface{}) string {
+	s := fmt.Sprintf("%s(value = %q, required = false", annotation, name)
+	if val != nil {
+		s += fmt.Sprintf(", defaultValue = %q", defaultValueString(val))
+	}
+	return s + ") "
 }
 
 func defaultValueAnnotation(val interface{}) string {
 	if val != nil {
-		switch v := val.(type) {
-		case string:
-			return fmt.Sprintf("@DefaultValue(%q) ", v)
-		case int8:
-			return fmt.Sprintf("@DefaultValue(\"%d\") ", v)
-		case int16:
-			return fmt.Sprintf("@DefaultValue(\"%d\") ", v)
-		case int32:
-			return fmt.Sprintf("@DefaultValue(\"%d\") ", v)
-		case int64:
-			return fmt.Sprintf("@DefaultValue(\"%d\") ", v)
-		case float32:
-			return fmt.Sprintf("@DefaultValue(\"%g\") ", v)
-		case float64:
-			return fmt.Sprintf("@DefaultValue(\"%g\") ", v)
-		default:
-			return fmt.Sprintf("@DefaultValue(\"%v\") ", v)
-		}
+		return fmt.Sprintf("@DefaultValue(%q) ", defaultValueString(val))
 	}
 	return ""
 }
 
+func defaultValueString(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case int8, int16, int32, int64:
+		return fmt.Sprintf("%d", v)
+	case float32, float64:
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 func (gen *javaServerGenerator) extendedValueAnnotation(annotations map[rdl.ExtendedAnnotation]string) string {
 	var buffer bytes.Buffer
 	for extendedKey, value := range annotations {
@@ -1115,6 +2100,10 @@ func (gen *javaServerGenerator) extendedValueAnnotation(annotations map[rdl.Exte
 			buffer.WriteString(generateAnnotation("@Min", value))
 		case "max":
 			buffer.WriteString(generateAnnotation("@Max", value))
+		case "decimal_min":
+			buffer.WriteString(generateAnnotation("@DecimalMin", value))
+		case "decimal_max":
+			buffer.WriteString(generateAnnotation("@DecimalMax", value))
 		case "size":
 			buffer.WriteString(generateAnnotation("@Size", value))
 		case "pattern":
@@ -1160,59 +2149,83 @@ func (gen *javaServerGenerator) extendedValueAnnotation(annotations map[rdl.Exte
 	return buffer.String()
 }
 
+// constraintAnnotations returns the constraints to validate a value of the type with. A value type carries the
+// constraints of its typedef, so that it is only validated in cascade.
+func (gen *javaServerGenerator) constraintAnnotations(rdlType rdl.TypeRef, explicit map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	if gen.isValueType(rdlType) {
+		return map[rdl.ExtendedAnnotation]string{"x_must_validate": explicit["x_must_validate"]}
+	}
+	return utils.ConstraintAnnotations(gen.registry, rdlType, gen.schema.Types, explicit)
+}
+
+func (gen *javaServerGenerator) isValueType(rdlType rdl.TypeRef) bool {
+	return gen.genValueTypes && utils.IsJavaValueType(gen.registry, gen.registry.FindType(rdlType))
+}
+
 func (gen *javaServerGenerator) generateImportClass(r *rdl.Resource) {
 	for _, v := range r.Inputs {
-		if len(v.Annotations) == 0 {
-			v.Annotations = utils.GetUserDefinedTypeAnnotations(v.Type, gen.schema.Types)
+		v.Annotations = gen.constraintAnnotations(v.Type, v.Annotations)
+		gen.generateAnnotationImportClass(v.Annotations)
+		if v.QueryParam != "" && gen.registry.FindBaseType(v.Type) == rdl.BaseTypeArray {
+			if t := gen.registry.FindType(v.Type); t.Variant == rdl.TypeVariantArrayTypeDef {
+				gen.generateAnnotationImportClass(gen.constraintAnnotations(t.ArrayTypeDef.Items, nil))
+			}
 		}
-		for extendedKey, value := range v.Annotations {
-			key := strings.TrimLeft(string(extendedKey), AnnotationPrefix)
-			switch key {
-			case "min":
-				gen.appendImportClass(JavaxConstraintPackage + ".Min")
-			case "max":
-				gen.appendImportClass(JavaxConstraintPackage + ".Max")
-			case "size":
-				gen.appendImportClass(JavaxConstraintPackage + ".Size")
-			case "pattern":
-				gen.appendImportClass(JavaxConstraintPackage + ".Pattern")
-			case "must_validate":
-				gen.appendImportClass(JavaxValidationPackage + ".Valid")
-				if value != "" {
-					gen.appendImportClass(JavaxValidationPackage + ".groups.Default")
-					gen.appendImportClass(JavaxValidationPackage + ".groups.ConvertGroup")
-				}
-			case "not_null":
-				gen.appendImportClass(JavaxConstraintPackage + ".NotNull")
-			case "null":
-				gen.appendImportClass(JavaxConstraintPackage + ".Null")
-			case "not_blank":
-				gen.appendImportClass(JavaxConstraintPackage + ".NotBlank")
-			case "not_empty":
-				gen.appendImportClass(JavaxConstraintPackage + ".NotEmpty")
-			case "country_code":
-				gen.appendImportClass(ParsecConstraintPackage + ".CountryCode")
-			case "currency":
-				gen.appendImportClass(ParsecConstraintPackage + ".ValidCurrency")
-			case "language_tag":
-				gen.appendImportClass(ParsecConstraintPackage + ".LanguageTag")
-			case "timezone":
-				gen.appendImportClass(ParsecConstraintPackage + ".ValidTimeZone")
-			case "named":
-				gen.appendImportClass(JavaxInjectPackage + ".Named")
-			case "date_time":
-				gen.appendImportClass(ParsecConstraintPackage + ".DateTime")
-			case "digits":
-				gen.appendImportClass(JavaxConstraintPackage + ".Digits")
-			default:
-				// unrecognized annotation, do nothing
+	}
+}
+
+func (gen *javaServerGenerator) generateAnnotationImportClass(annotations map[rdl.ExtendedAnnotation]string) {
+	for extendedKey, value := range annotations {
+		key := strings.TrimLeft(string(extendedKey), AnnotationPrefix)
+		switch key {
+		case "min":
+			gen.appendImportClass(JavaxConstraintPackage + ".Min")
+		case "max":
+			gen.appendImportClass(JavaxConstraintPackage + ".Max")
+		case "decimal_min":
+			gen.appendImportClass(JavaxConstraintPackage + ".DecimalMin")
+		case "decimal_max":
+			gen.appendImportClass(JavaxConstraintPackage + ".DecimalMax")
+		case "size":
+			gen.appendImportClass(JavaxConstraintPackage + ".Size")
+		case "pattern":
+			gen.appendImportClass(JavaxConstraintPackage + ".Pattern")
+		case "must_validate":
+			gen.appendImportClass(JavaxValidationPackage + ".Valid")
+			if value != "" {
+				gen.appendImportClass(JavaxValidationPackage + ".groups.Default")
+				gen.appendImportClass(JavaxValidationPackage + ".groups.ConvertGroup")
 			}
+		case "not_null":
+			gen.appendImportClass(JavaxConstraintPackage + ".NotNull")
+		case "null":
+			gen.appendImportClass(JavaxConstraintPackage + ".Null")
+		case "not_blank":
+			gen.appendImportClass(JavaxConstraintPackage + ".NotBlank")
+		case "not_empty":
+			gen.appendImportClass(JavaxConstraintPackage + ".NotEmpty")
+		case "country_code":
+			gen.appendImportClass(ParsecConstraintPackage + ".CountryCode")
+		case "currency":
+			gen.appendImportClass(ParsecConstraintPackage + ".ValidCurrency")
+		case "language_tag":
+			gen.appendImportClass(ParsecConstraintPackage + ".LanguageTag")
+		case "timezone":
+			gen.appendImportClass(ParsecConstraintPackage + ".ValidTimeZone")
+		case "named":
+			gen.appendImportClass(JavaxInjectPackage + ".Named")
+		case "date_time":
+			gen.appendImportClass(ParsecConstraintPackage + ".DateTime")
+		case "digits":
+			gen.appendImportClass(JavaxConstraintPackage + ".Digits")
+		default:
+			// unrecognized annotation, do nothing
 		}
 	}
 }
 
 func (gen *javaServerGenerator) appendImportClass(importClass string) {
-	importString := fmt.Sprintf("import %s;\n", importClass)
+	importString := fmt.Sprintf("import %s;\n", utils.JavaEEClass(importClass, gen.jakarta))
 	alreadyExists := false
 	for _, value := range gen.imports {
 		if importString == value {
@@ -1249,11 +2262,14 @@ func (gen *javaServerGenerator) serverMethodSignature(r *rdl.Resource) string {
 	//FIX: if nocontent, return nothing, have a void result, and don't "@Produces" anything
 	ver, err := utils.GetSchemaVersionOrDefault(gen.schema, 1)
 	checkErr(err)
-	methName, params := javaMethodName(reg, r, gen.genUsingPath, gen.isPcSuffix, ver)
+	methName, params := javaMethodName(reg, r, gen.genUsingPath, gen.isPcSuffix, ver, gen.genValueTypes)
 	sparams := ""
 	if len(params) > 0 {
 		sparams = ", " + strings.Join(params, ", ")
 	}
+	if gen.async == AsyncFutureMode {
+		return "public CompletableFuture<" + gen.futureType(r) + "> " + methName + "(ResourceContext context" + sparams + ")"
+	}
 	returnType = gen.handlerReturnType(r, methName, returnType)
 	if returnType == "void" {
 		sparams = sparams + ", " + utils.Capitalize(methName) + "Result result"
@@ -1263,50 +2279,19 @@ func (gen *javaServerGenerator) serverMethodSignature(r *rdl.Resource) string {
 	return "public " + returnType + " " + methName + "(ResourceContext context" + sparams + ")"
 }
 
-func javaMethodName(reg rdl.TypeRegistry, r *rdl.Resource, usePath bool, isPcSuffix bool, apiVer int32) (string, []string) {
+func javaMethodName(reg rdl.TypeRegistry, r *rdl.Resource, usePath bool, isPcSuffix bool, apiVer int32, genValueTypes bool) (string, []string) {
 	var params []string
-	bodyType := r.Type
 	for _, v := range r.Inputs {
 		if v.Context != "" { //ignore these legacy things
 			log.Println("Warning: v1 style context param ignored:", v.Name, v.Context)
 			continue
 		}
 		k := v.Name
-		if v.QueryParam == "" && !v.PathParam && v.Header == "" {
-			bodyType = v.Type
-		}
 		//rest_core always uses the boxed type
 		optional := true
-		params = append(params, utils.JavaType(reg, v.Type, optional, "", "", isPcSuffix, apiVer)+" "+javaName(k))
-	}
-	if r.Name != "" {
-		return utils.Uncapitalize(string(r.Name)), params
-	} else {
-		if method := strings.ToLower(r.Method); usePath {
-			var buffer bytes.Buffer
-			pieces := strings.Split(r.Path, "/")
-			buffer.WriteString(method)
-			counter := 0
-			for _, piece := range pieces {
-				if piece != "" {
-					if strings.Contains(piece, "{") && strings.Contains(piece, "}") {
-						counter++
-						piece = strings.TrimPrefix(piece, "{")
-						piece = strings.TrimSuffix(piece, "}")
-						if counter == 1 {
-							buffer.WriteString("By")
-						} else {
-							buffer.WriteString("And")
-						}
-					}
-					buffer.WriteString(utils.Capitalize(piece))
-				}
-			}
-			return buffer.String(), params
-		} else {
-			return method + string(bodyType), params
-		}
+		params = append(params, utils.JavaType(reg, v.Type, optional, "", "", isPcSuffix, apiVer, genValueTypes)+" "+javaName(k))
 	}
+	return utils.JavaMethodName(r, usePath), params
 }
 
 func javaName(name rdl.Identifier) string {
@@ -1321,7 +2306,7 @@ func javaName(name rdl.Identifier) string {
 func (gen *javaServerGenerator) generateStructFieldType(rdlType rdl.TypeRef, r *rdl.Resource) string {
 	t := gen.registry.FindType(rdlType)
 	subItems := t.ArrayTypeDef.Items
-	annotations := utils.GetUserDefinedTypeAnnotations(subItems, gen.schema.Types)
+	annotations := gen.constraintAnnotations(subItems, nil)
 	pdecl := ""
 	if len(annotations) > 0 {
 		pdecl = gen.extendedValueAnnotation(annotations)
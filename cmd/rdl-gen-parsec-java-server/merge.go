@@ -0,0 +1,125 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// merge the stubs of new resources into an existing HandlerImpl
+//
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/ardielle/ardielle-go/rdl"
+)
+
+// handlerMethodPattern matches the declaration of a handler method, up to its parameters after the ResourceContext.
+var handlerMethodPattern = regexp.MustCompile(`public\s+([^(;={}]+?)\s+(\w+)\s*\(\s*ResourceContext\s+\w+\s*(,[^)]*)?\)`)
+
+// handlerStub returns the method of the HandlerImpl for the resource, which returns nothing yet.
+func (gen *javaServerGenerator) handlerStub(r *rdl.Resource) string {
+	sig := gen.serverMethodSignature(r)
+	body := "        return null;\n"
+	if gen.async == AsyncFutureMode {
+		body = "        return CompletableFuture.completedFuture(null);\n"
+	} else if strings.HasPrefix(sig, "public void ") {
+		body = ""
+	}
+	return "\n    @Override\n    " + sig + " {\n" + body + "    }"
+}
+
+// mergeHandlerImpl appends the stubs of the handler methods missing from an existing HandlerImpl, and the imports they
+// may need. The methods already there are left as they are: the warnings tell which ones no longer match a resource.
+func (gen *javaServerGenerator) mergeHandlerImpl(filePath string) ([]string, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	existing := make(map[string]string)
+	var existingNames []string
+	for _, m := range handlerMethodPattern.FindAllStringSubmatch(content, -1) {
+		existing[m[2]] = handlerMethodKey(m)
+		existingNames = append(existingNames, m[2])
+	}
+	var warnings []string
+	var stubs []string
+	expected := make(map[string]bool)
+	for _, r := range gen.schema.Resources {
+		m := handlerMethodPattern.FindStringSubmatch(gen.serverMethodSignature(r))
+		expected[m[2]] = true
+		key, ok := existing[m[2]]
+		if !ok {
+			stubs = append(stubs, gen.handlerStub(r))
+		} else if key != handlerMethodKey(m) {
+			warnings = append(warnings, fmt.Sprintf("%s changed in %s: %s, was %s", m[2], filePath, handlerMethodKey(m), key))
+		}
+	}
+	for _, name := range existingNames {
+		if !expected[name] {
+			warnings = append(warnings, fmt.Sprintf("%s in %s no longer matches a resource", name, filePath))
+		}
+	}
+	if len(stubs) == 0 {
+		return warnings, nil
+	}
+	var imports []string
+	for _, imp := range gen.imports {
+		if !strings.Contains(content, strings.TrimSuffix(imp, "\n")) {
+			imports = append(imports, imp)
+		}
+	}
+	if len(imports) > 0 {
+		i := strings.Index(content, "\nimport ")
+		if i < 0 {
+			i = strings.Index(content, ";\n") + 1
+		}
+		content = content[:i+1] + strings.Join(imports, "") + content[i+1:]
+	}
+	end := strings.LastIndex(content, "}")
+	if end < 0 {
+		return nil, fmt.Errorf("no class body in %s", filePath)
+	}
+	content = strings.TrimRight(content[:end], " \t\n") + "\n" + strings.Join(stubs, "\n") + "\n" + content[end:]
+	return warnings, ioutil.WriteFile(filePath, []byte(content), 0644)
+}
+
+// handlerMethodKey returns the signature of a matched handler method without the parameter names and the spaces.
+func handlerMethodKey(m []string) string {
+	types := []string{"ResourceContext"}
+	for _, param := range splitParams(strings.TrimPrefix(m[3], ",")) {
+		var fields []string
+		for _, field := range strings.Fields(param) {
+			if field != "final" && !strings.HasPrefix(field, "@") {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) > 1 {
+			types = append(types, strings.Join(fields[:len(fields)-1], ""))
+		}
+	}
+	return strings.Join(strings.Fields(m[1]), "") + " " + m[2] + "(" + strings.Join(types, ", ") + ")"
+}
+
+// splitParams splits a parameter list on the commas that are not in a type argument.
+func splitParams(params string) []string {
+	var split []string
+	depth, start := 0, 0
+	for i, c := range params {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				split = append(split, params[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(split, params[start:])
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// merge the documents of several RDL schemas into one
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// bundleSwagger merges the documents into one named after the bundle. The paths of every document are put under
+// its own base path, less the common prefix (the finalName) that becomes the basePath of the bundle. Definitions
+// and security definitions that several documents share must be identical, and no operation may be defined twice
+// nor take the operationId of another, which names its fixtures. The names of the schemas the documents come from are used in the errors.
+func bundleSwagger(name string, basePath string, names []string, docs []*SwaggerDoc) (*SwaggerDoc, error) {
+	bundle := new(SwaggerDoc)
+	bundle.Swagger = "2.0"
+	bundle.Schemes = []string{}
+	bundle.BasePath = basePath
+	if bundle.BasePath == "" {
+		bundle.BasePath = "/"
+	}
+	bundle.Info = new(SwaggerInfo)
+	bundle.Info.Title = "The " + name + " API"
+	paths := make(map[string]map[string]*SwaggerAction)
+	defs := make(map[string]*SwaggerType)
+	owners := make(map[string]string)
+	for i, doc := range docs {
+		schemaName := names[i]
+		if i == 0 {
+			bundle.Host = doc.Host
+			bundle.Schemes = doc.Schemes
+			bundle.Info.Version = doc.Info.Version
+		} else if doc.Info.Version != bundle.Info.Version {
+			// the schemas have their own versions, the bundle has none
+			bundle.Info.Version = ""
+		}
+		prefix := strings.TrimSuffix(strings.TrimPrefix(doc.BasePath, basePath), "/")
+		for path, actions := range doc.Paths {
+			bundled, ok := paths[prefix+path]
+			if !ok {
+				bundled = make(map[string]*SwaggerAction)
+				paths[prefix+path] = bundled
+			}
+			for meth, action := range actions {
+				if _, ok := bundled[meth]; ok {
+					return nil, fmt.Errorf("%s %s of %s is already defined by %s", strings.ToUpper(meth), prefix+path, schemaName, owners[meth+" "+prefix+path])
+				}
+				if owner, ok := owners["operationId "+action.OperationID]; ok && action.OperationID != "" {
+					return nil, fmt.Errorf("operationId %s of %s is already used by %s", action.OperationID, schemaName, owner)
+				}
+				bundled[meth] = action
+				owners[meth+" "+prefix+path] = schemaName
+				owners["operationId "+action.OperationID] = schemaName
+			}
+		}
+		for _, tag := range doc.Tags {
+			if !bundledTag(bundle.Tags, tag.Name) {
+				bundle.Tags = append(bundle.Tags, tag)
+			}
+		}
+		for tName, def := range doc.Definitions {
+			if err := bundleDefinition(defs, tName, def, schemaName, owners); err != nil {
+				return nil, err
+			}
+		}
+		for sName, scheme := range doc.SecurityDefinitions {
+			if bundle.SecurityDefinitions == nil {
+				bundle.SecurityDefinitions = make(map[string]*SwaggerSecurityScheme)
+			}
+			if prev, ok := bundle.SecurityDefinitions[sName]; ok && *prev != *scheme {
+				return nil, fmt.Errorf("security definition %s of %s conflicts with the one of %s", sName, schemaName, owners["securityDefinitions "+sName])
+			}
+			bundle.SecurityDefinitions[sName] = scheme
+			owners["securityDefinitions "+sName] = schemaName
+		}
+	}
+	if len(paths) > 0 {
+		bundle.Paths = paths
+	}
+	bundle.Definitions = defs
+	sort.Slice(bundle.Tags, func(i, j int) bool { return bundle.Tags[i].Name < bundle.Tags[j].Name })
+	return bundle, nil
+}
+
+func bundledTag(tags []*SwaggerTag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleDefinition adds the definition of a document to those of the bundle, unless an identical one is there already.
+func bundleDefinition(defs map[string]*SwaggerType, tName string, def *SwaggerType, schemaName string, owners map[string]string) error {
+	prev, ok := defs[tName]
+	if !ok {
+		defs[tName] = def
+		owners["definitions "+tName] = schemaName
+		return nil
+	}
+	j1, err := json.Marshal(prev)
+	if err != nil {
+		return err
+	}
+	j2, err := json.Marshal(def)
+	if err != nil {
+		return err
+	}
+	if string(j1) != string(j2) {
+		return fmt.Errorf("definition %s of %s conflicts with the one of %s", tName, schemaName, owners["definitions "+tName])
+	}
+	return nil
+}
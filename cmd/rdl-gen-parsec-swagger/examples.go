@@ -0,0 +1,332 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// synthesize example values from the RDL type graph
+//
+
+import (
+	"encoding/json"
+	"github.com/ardielle/ardielle-go/rdl"
+	"github.com/iancoleman/orderedmap"
+	"github.com/yahoo/parsec-rdl-gen/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExampleString    = "string"
+	ExampleTimestamp = "2020-01-01T00:00:00.000Z"
+	ExampleUUID      = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	ExampleBytes     = "ZXhhbXBsZQ=="
+)
+
+// exampleValue builds an example value of the given type, using the inline items and keys of a struct field if set.
+// The x_example annotations of struct fields win over the synthesized values. Structs that are already being built
+// are skipped to break cycles, so optional recursive fields are left out.
+func exampleValue(reg rdl.TypeRegistry, typeRef rdl.TypeRef, items rdl.TypeRef, keys rdl.TypeRef, building map[rdl.TypeRef]bool) interface{} {
+	t := reg.FindType(typeRef)
+	if t == nil {
+		return nil
+	}
+	switch reg.BaseType(t) {
+	case rdl.BaseTypeStruct:
+		if building[typeRef] {
+			return nil
+		}
+		building[typeRef] = true
+		defer delete(building, typeRef)
+		obj := orderedmap.New()
+		for _, f := range utils.FlattenedFields(reg, t) {
+			var v interface{}
+			if example := f.Annotations[ExampleAnnotationKey]; example != "" {
+				v = parseExample(reg, f.Type, f.Items, example)
+			} else {
+				v = exampleValue(reg, f.Type, f.Items, f.Keys, building)
+			}
+			if v != nil {
+				obj.Set(string(f.Name), v)
+			}
+		}
+		return obj
+	case rdl.BaseTypeEnum:
+		if len(t.EnumTypeDef.Elements) == 0 {
+			return nil
+		}
+		return string(t.EnumTypeDef.Elements[0].Symbol)
+	case rdl.BaseTypeUnion:
+		if len(t.UnionTypeDef.Variants) == 0 {
+			return nil
+		}
+		return exampleValue(reg, t.UnionTypeDef.Variants[0], "", "", building)
+	case rdl.BaseTypeArray:
+		st := new(SwaggerType)
+		addSwaggerFacets(reg, st, typeRef)
+		if items == "" && t.Variant == rdl.TypeVariantArrayTypeDef {
+			items = t.ArrayTypeDef.Items
+		}
+		n := exampleSize(st.MinItems, st.MaxItems)
+		arr := make([]interface{}, 0, n)
+		if items == "" || items == "Any" {
+			return arr
+		}
+		for i := 0; i < n; i++ {
+			v := exampleValue(reg, items, "", "", building)
+			if v == nil {
+				break
+			}
+			arr = append(arr, v)
+		}
+		return arr
+	case rdl.BaseTypeMap:
+		st := new(SwaggerType)
+		addSwaggerFacets(reg, st, typeRef)
+		if t.Variant == rdl.TypeVariantMapTypeDef {
+			if items == "" {
+				items = t.MapTypeDef.Items
+			}
+			if keys == "" {
+				keys = t.MapTypeDef.Keys
+			}
+		}
+		obj := orderedmap.New()
+		if items == "" || items == "Any" || exampleSize(st.MinProperties, st.MaxProperties) == 0 {
+			return obj
+		}
+		key := "key"
+		if keys != "" && keys != "String" {
+			if k, ok := exampleValue(reg, keys, "", "", building).(string); ok {
+				key = k
+			}
+		}
+		if v := exampleValue(reg, items, "", "", building); v != nil {
+			obj.Set(key, v)
+		}
+		return obj
+	case rdl.BaseTypeAny:
+		return nil
+	}
+	return scalarExample(makeSwaggerTypeSchema(reg, typeRef, "", ""))
+}
+
+// scalarExample builds an example of a string, number or boolean schema that satisfies its facets.
+func scalarExample(st *SwaggerType) interface{} {
+	switch st.Type {
+	case "string":
+		if len(st.Enum) > 0 {
+			return st.Enum[0]
+		}
+		switch st.Format {
+		case "date-time":
+			return ExampleTimestamp
+		case "uuid":
+			return ExampleUUID
+		case "byte":
+			return ExampleBytes
+		}
+		if st.Pattern != "" {
+			if s, ok := patternExample(st.Pattern); ok {
+				return s
+			}
+		}
+		s := ExampleString
+		if st.MinLength != nil && len(s) < int(*st.MinLength) {
+			s += strings.Repeat("x", int(*st.MinLength)-len(s))
+		}
+		if st.MaxLength != nil && len(s) > int(*st.MaxLength) {
+			s = s[:*st.MaxLength]
+		}
+		return s
+	case "integer", "number":
+		// the lower bound if there is one, zero unless that is above the upper bound otherwise
+		if st.Minimum != nil {
+			return st.Minimum
+		}
+		if st.Maximum != nil && toFloat(st.Maximum) < 0 {
+			return st.Maximum
+		}
+		return 0
+	case "boolean":
+		return true
+	}
+	return nil
+}
+
+func toFloat(n interface{}) float64 {
+	switch v := n.(type) {
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// exampleSize is the number of elements of an example array or map, one unless the bounds say otherwise.
+func exampleSize(min *int32, max *int32) int {
+	n := 1
+	if min != nil && int(*min) > n {
+		n = int(*min)
+	}
+	if max != nil && int(*max) < n {
+		n = int(*max)
+	}
+	return n
+}
+
+// patternExample builds the shortest string matching a simple pattern, taking the first alternative and the
+// first character of every class. It gives up on patterns the result does not match.
+func patternExample(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var sb strings.Builder
+	if !writePatternExample(&sb, re.Simplify()) {
+		return "", false
+	}
+	s := sb.String()
+	if matched, err := regexp.MatchString("^(?:"+pattern+")$", s); err != nil || !matched {
+		return "", false
+	}
+	return s, true
+}
+
+func writePatternExample(sb *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+		return true
+	case syntax.OpCharClass:
+		if len(re.Rune) < 2 {
+			return false
+		}
+		sb.WriteRune(classExample(re.Rune))
+		return true
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteRune('x')
+		return true
+	case syntax.OpCapture, syntax.OpPlus:
+		return writePatternExample(sb, re.Sub[0])
+	case syntax.OpStar, syntax.OpQuest:
+		return true
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			if !writePatternExample(sb, re.Sub[0]) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !writePatternExample(sb, sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		return writePatternExample(sb, re.Sub[0])
+	}
+	return false
+}
+
+// classExample picks a readable character of a character class, given as pairs of rune ranges.
+func classExample(ranges []rune) rune {
+	for _, r := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	return ranges[0]
+}
+
+// parseExample turns the x_example of a field into a value of the field's type. Array fields take the example
+// for their items, anything that does not parse as the scalar type is kept as a string.
+func parseExample(reg rdl.TypeRegistry, typeRef rdl.TypeRef, items rdl.TypeRef, example string) interface{} {
+	switch reg.FindBaseType(typeRef) {
+	case rdl.BaseTypeArray:
+		if items == "" {
+			if t := reg.FindType(typeRef); t != nil && t.Variant == rdl.TypeVariantArrayTypeDef {
+				items = t.ArrayTypeDef.Items
+			}
+		}
+		if items != "" {
+			return []interface{}{parseExample(reg, items, "", example)}
+		}
+	case rdl.BaseTypeInt8, rdl.BaseTypeInt16, rdl.BaseTypeInt32, rdl.BaseTypeInt64:
+		if v, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return v
+		}
+	case rdl.BaseTypeFloat32, rdl.BaseTypeFloat64:
+		if v, err := strconv.ParseFloat(example, 64); err == nil {
+			return v
+		}
+	case rdl.BaseTypeBool:
+		if v, err := strconv.ParseBool(example); err == nil {
+			return v
+		}
+	}
+	return example
+}
+
+// writeSwaggerFixtures writes the example request and response bodies of every operation as JSON files named
+// after the operationId, e.g. postOrders_request.json and postOrders_201.json.
+func writeSwaggerFixtures(swag *SwaggerDoc, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var paths []string
+	for path := range swag.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, action := range swag.Paths[path] {
+			for _, param := range action.Parameters {
+				if param.In == "body" && param.Example != nil {
+					if err := writeFixture(dir, action.OperationID+"_request.json", param.Example); err != nil {
+						return err
+					}
+				}
+			}
+			for code, resp := range action.Responses {
+				for _, example := range resp.Examples {
+					if err := writeFixture(dir, action.OperationID+"_"+code+".json", example); err != nil {
+						return err
+					}
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeFixture(dir string, name string, example interface{}) error {
+	j, err := json.MarshalIndent(example, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, name), append(j, '\n'), 0644)
+}
@@ -0,0 +1,164 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// pass RDL extended annotations through as vendor extensions
+//
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ardielle/ardielle-go/rdl"
+	"github.com/yahoo/parsec-rdl-gen/utils"
+	"sort"
+	"strings"
+)
+
+// extensionFilter decides which x_ annotations become vendor extensions. Annotations the generator interprets
+// itself (x_example, x_discriminator, x_deprecated and x_tag_*) are never passed through.
+type extensionFilter struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// newExtensionFilter parses a comma separated list of annotation names, names prefixed with '-' are denied.
+// If any name is allowed, only the allowed names pass. Names may be given as x_foo or x-foo.
+func newExtensionFilter(spec string) *extensionFilter {
+	f := &extensionFilter{make(map[string]bool), make(map[string]bool)}
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "-") {
+			f.deny[vendorExtensionName(name[1:])] = true
+		} else if name != "" {
+			f.allow[vendorExtensionName(name)] = true
+		}
+	}
+	return f
+}
+
+// extensions returns the vendor extensions for the given annotations, nil if there are none.
+// Annotations without a value are markers and become true.
+func (f *extensionFilter) extensions(annotations map[rdl.ExtendedAnnotation]string) map[string]interface{} {
+	var ext map[string]interface{}
+	for k, v := range annotations {
+		key := string(k)
+		if key == ExampleAnnotationKey || key == DiscriminatorAnnotationKey || key == utils.DeprecatedAnnotationKey ||
+			strings.HasPrefix(key, "x_tag_") {
+			continue
+		}
+		name := vendorExtensionName(key)
+		if f.deny[name] || (len(f.allow) > 0 && !f.allow[name]) {
+			continue
+		}
+		if ext == nil {
+			ext = make(map[string]interface{})
+		}
+		if v == "" {
+			ext[name] = true
+		} else {
+			ext[name] = v
+		}
+	}
+	return ext
+}
+
+// inlineAnnotations merges the annotations of a string, number or bytes typedef into those of a field of that type,
+// since such typedefs have no definition of their own. The annotations of the field win.
+func inlineAnnotations(reg rdl.TypeRegistry, typeRef rdl.TypeRef, annotations map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	t := reg.FindType(typeRef)
+	if t == nil || reg.IsBaseTypeName(typeRef) {
+		return annotations
+	}
+	var typeAnnotations map[rdl.ExtendedAnnotation]string
+	switch t.Variant {
+	case rdl.TypeVariantStringTypeDef:
+		typeAnnotations = t.StringTypeDef.Annotations
+	case rdl.TypeVariantNumberTypeDef:
+		typeAnnotations = t.NumberTypeDef.Annotations
+	case rdl.TypeVariantBytesTypeDef:
+		typeAnnotations = t.BytesTypeDef.Annotations
+	}
+	if len(typeAnnotations) == 0 {
+		return annotations
+	}
+	merged := make(map[rdl.ExtendedAnnotation]string)
+	for k, v := range typeAnnotations {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+// vendorExtensionName turns an annotation name like x_rate_limit into x-rate-limit.
+func vendorExtensionName(name string) string {
+	return strings.Replace(name, "_", "-", -1)
+}
+
+// marshalWithExtensions marshals v as a JSON object and appends the vendor extensions to it, sorted by name.
+func marshalWithExtensions(v interface{}, ext map[string]interface{}) ([]byte, error) {
+	j, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return j, err
+	}
+	var names []string
+	for name := range ext {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := bytes.NewBuffer(j[:len(j)-1])
+	for i, name := range names {
+		if i > 0 || len(j) > 2 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(name)
+		val, err := json.Marshal(ext[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON adds the vendor extensions of the action.
+func (a *SwaggerAction) MarshalJSON() ([]byte, error) {
+	type action SwaggerAction
+	return marshalWithExtensions((*action)(a), a.Extensions)
+}
+
+// MarshalJSON adds the vendor extensions of the parameter.
+func (p *SwaggerParameter) MarshalJSON() ([]byte, error) {
+	type parameter SwaggerParameter
+	return marshalWithExtensions((*parameter)(p), p.Extensions)
+}
+
+// MarshalJSON adds the vendor extensions of the schema.
+func (t *SwaggerType) MarshalJSON() ([]byte, error) {
+	type schema SwaggerType
+	return marshalWithExtensions((*schema)(t), t.Extensions)
+}
+
+// MarshalJSON adds the vendor extensions of the operation.
+func (op *OpenAPIOperation) MarshalJSON() ([]byte, error) {
+	type operation OpenAPIOperation
+	return marshalWithExtensions((*operation)(op), op.Extensions)
+}
+
+// MarshalJSON adds the vendor extensions of the parameter.
+func (p *OpenAPIParameter) MarshalJSON() ([]byte, error) {
+	type parameter OpenAPIParameter
+	return marshalWithExtensions((*parameter)(p), p.Extensions)
+}
+
+// MarshalJSON adds the vendor extensions of the request body.
+func (b *OpenAPIRequestBody) MarshalJSON() ([]byte, error) {
+	type requestBody OpenAPIRequestBody
+	return marshalWithExtensions((*requestBody)(b), b.Extensions)
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"github.com/ardielle/ardielle-go/rdl"
 	"os"
+	"strings"
 )
 
 func TestGenerateImpl(test *testing.T) {
@@ -20,7 +21,7 @@ func TestGenerateImpl(test *testing.T) {
 	checkErrInTest(err, "unmarshal sample data fail", test)
 
 	genParsecError := true
-	swaggerData, err := swagger(&schema, genParsecError, "", "", "")
+	swaggerData, err := swagger(&schema, genParsecError, "", "", "", "Authorization", false, true, false, "")
 	checkErrInTest(err, "cannot generate swagger", test)
 	j, err := json.MarshalIndent(swaggerData, "", "    ")
 	checkErrInTest(err, "cannot marshal swagger", test)
@@ -43,7 +44,7 @@ func TestNoTypes(test *testing.T) {
 	checkErrInTest(err, "unmarshal sample data fail", test)
 
 	genParsecError := true
-	swaggerData, err := swagger(&schema, genParsecError, "", "", "")
+	swaggerData, err := swagger(&schema, genParsecError, "", "", "", "Authorization", false, true, false, "")
 	checkErrInTest(err, "cannot generate swagger", test)
 	j, err := json.MarshalIndent(swaggerData, "", "    ")
 	checkErrInTest(err, "cannot marshal swagger", test)
@@ -57,6 +58,560 @@ func TestNoTypes(test *testing.T) {
 	}
 }
 
+func TestGenerateOpenAPI(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/multipleType.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	genParsecError := true
+	swaggerData, err := swagger(&schema, genParsecError, "https", "", "api.example.com", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/multipleType_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("sample openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestUnionTypes(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/union.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, true, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/union_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("union swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/union_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("union openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestDiscriminatorVariants(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/union.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	reg := rdl.NewTypeRegistry(&schema)
+	pet := reg.FindType("Pet").UnionTypeDef
+	if missing := variantsWithoutField(reg, pet, "kind"); len(missing) != 0 {
+		test.Errorf("every variant of Pet has a kind, but got %v", missing)
+	}
+	if missing := variantsWithoutField(reg, pet, "lives"); len(missing) != 1 || missing[0] != "Dog" {
+		test.Errorf("only Dog should lack lives, but got %v", missing)
+	}
+}
+
+func TestAuthSecurity(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/auth.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Athenz-Principal-Auth", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/auth_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("auth swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/auth_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("auth openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestResponseHeaders(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/headers.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/headers_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("headers swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/headers_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("headers openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestInheritanceAllOf(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/inheritance.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", true, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/inheritance_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("inheritance swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/inheritance_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("inheritance openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestTypeFacets(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/facets.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/facets_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("facets swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/facets_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("facets openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestOperationIdWithoutPath(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/auth.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, false, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+
+	// the two GET resources share a handler method name, so that they are named after their paths
+	expected := map[string]string{"get": "getUsersByName", "put": "putUser"}
+	for meth, action := range swaggerData.Paths["/users/{name}"] {
+		if action.OperationID != expected[meth] {
+			test.Errorf("operationId of %s not generated as expected, real: %s, expected: %s", meth, action.OperationID, expected[meth])
+		}
+	}
+	if action := swaggerData.Paths["/public/users/{name}"]["get"]; action.OperationID != "getPublicUsersByName" {
+		test.Errorf("operationId of the public get not generated as expected, real: %s", action.OperationID)
+	}
+
+	// the explicit names must be unique
+	schema.Resources[0].Name = "fetchUser"
+	schema.Resources[2].Name = "fetchUser"
+	_, err = swagger(&schema, false, "", "", "", "Authorization", false, false, false, "")
+	if err == nil || err.Error() != "operationId fetchUser of GET /public/users/{name} is already used by GET /users/{name}" {
+		test.Errorf("duplicate operationIds not detected as expected, real: %v", err)
+	}
+}
+
+func TestExamplePayloads(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/examples.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, true, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/examples_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("examples swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/examples_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("examples openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestExampleFixtures(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/examples.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	outdir, err := ioutil.TempDir(".", "testOutput-")
+	checkErrInTest(err, "cannot create output dir", test)
+	defer os.RemoveAll(outdir)
+
+	err = ExportToSwagger(&schema, outdir, false, "", "", "", false, "Authorization", false, true, false, outdir+"/fixtures", "", JSONFormat, nil, nil)
+	checkErrInTest(err, "cannot export swagger", test)
+
+	for _, name := range []string{"postOrders_request.json", "postOrders_201.json", "getOrdersById_200.json"} {
+		fixture, err := ioutil.ReadFile(outdir + "/fixtures/" + name)
+		if err != nil {
+			test.Errorf("fixture %s not generated", name)
+			continue
+		}
+		var order map[string]interface{}
+		if err = json.Unmarshal(fixture, &order); err != nil || order["status"] != "PENDING" || order["priority"] != float64(5) {
+			test.Errorf("fixture %s not generated as expected, real: \n%s\n", name, string(fixture))
+		}
+	}
+}
+
+func TestSwaggerInfo(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/inheritance.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	outdir, err := ioutil.TempDir(".", "testOutput-")
+	checkErrInTest(err, "cannot create output dir", test)
+	defer os.RemoveAll(outdir)
+
+	info := &SwaggerInfo{Title: "Pet Store", TermsOfService: "https://example.com/terms"}
+	info.Contact = &SwaggerContact{"Pets Team", "", "pets@example.com"}
+	info.License = &SwaggerLicense{"Apache 2.0", "https://www.apache.org/licenses/LICENSE-2.0"}
+	err = ExportToSwagger(&schema, outdir, false, "", "", "", true, "Authorization", false, true, false, "", "", JSONFormat,
+		info, []string{"https://pets.example.com/inheritance/v1"})
+	checkErrInTest(err, "cannot export openapi", test)
+
+	j, err := ioutil.ReadFile(outdir + "/inheritance_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+	var doc OpenAPIDoc
+	checkErrInTest(json.Unmarshal(j, &doc), "cannot unmarshal openapi", test)
+
+	// the version and description still come from the schema
+	if doc.Info.Title != "Pet Store" || doc.Info.Version != "1" || doc.Info.TermsOfService != "https://example.com/terms" ||
+		*doc.Info.Contact != *info.Contact || *doc.Info.License != *info.License {
+		test.Errorf("info not generated as expected, real: \n%s\n", string(j))
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://pets.example.com/inheritance/v1" {
+		test.Errorf("servers not generated as expected, real: \n%s\n", string(j))
+	}
+	if len(doc.Tags) != 1 || doc.Tags[0].Name != "Dog" || doc.Tags[0].Description != "a dog is a pet" {
+		test.Errorf("tags not generated as expected, real: \n%s\n", string(j))
+	}
+}
+
+func TestVendorExtensions(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("extensions swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("extensions openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestDeprecation(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/deprecation.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/deprecation_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("deprecation swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/deprecation_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("deprecation openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestQueryParams(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/query.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/query_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("query parameters swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+
+	j, err = json.MarshalIndent(openAPIDoc(swaggerData), "", "    ")
+	checkErrInTest(err, "cannot marshal openapi", test)
+
+	expectedSampleOpenAPI, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/query_openapi.json")
+	checkErrInTest(err, "cannot read openapi json file", test)
+
+	if (string(j) != string(expectedSampleOpenAPI)) {
+		test.Errorf("query parameters openapi json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleOpenAPI))
+	}
+}
+
+func TestVendorExtensionFilter(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "x_rate_limit,x-owner")
+	checkErrInTest(err, "cannot generate swagger", test)
+	get := swaggerData.Paths["/accounts/{id}"]["get"]
+	if len(get.Extensions) != 1 || get.Extensions["x-rate-limit"] != "100" || get.Parameters[0].Extensions != nil {
+		test.Errorf("allowed extensions not generated as expected, real: %v, %v", get.Extensions, get.Parameters[0].Extensions)
+	}
+	if account := swaggerData.Definitions["Account"]; len(account.Extensions) != 1 || account.Extensions["x-owner"] != "accounts-team" {
+		test.Errorf("allowed extensions not generated as expected, real: %v", account.Extensions)
+	}
+
+	swaggerData, err = swagger(&schema, false, "", "", "", "Authorization", false, true, false, "-x_internal,-x_pii")
+	checkErrInTest(err, "cannot generate swagger", test)
+	get = swaggerData.Paths["/accounts/{id}"]["get"]
+	if get.Extensions["x-rate-limit"] != "100" || get.Parameters[0].Extensions != nil || get.Parameters[1].Extensions != nil {
+		test.Errorf("denied extensions not left out as expected, real: %v, %v, %v",
+			get.Extensions, get.Parameters[0].Extensions, get.Parameters[1].Extensions)
+	}
+	if account := swaggerData.Definitions["Account"]; len(account.Extensions) != 1 || account.Extensions["x-owner"] != "accounts-team" {
+		test.Errorf("denied extensions not left out as expected, real: %v", account.Extensions)
+	}
+}
+
+func TestYAMLFormat(test *testing.T) {
+	data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions.json")
+	checkErrInTest(err, "can not read sample file", test)
+
+	var schema rdl.Schema
+	err = json.Unmarshal(data, &schema)
+	checkErrInTest(err, "unmarshal sample data fail", test)
+
+	swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+	checkErrInTest(err, "cannot generate swagger", test)
+	y, err := marshalSwagger(swaggerData, YAMLFormat)
+	checkErrInTest(err, "cannot marshal swagger as yaml", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/extensions_swagger.yaml")
+	checkErrInTest(err, "cannot read swagger yaml file", test)
+
+	if (string(y) != string(expectedSampleSwagger)) {
+		test.Errorf("swagger yaml not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(y), string(expectedSampleSwagger))
+	}
+}
+
+func TestBundle(test *testing.T) {
+	var names []string
+	var docs []*SwaggerDoc
+	for _, name := range []string{"auth", "extensions"} {
+		data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/" + name + ".json")
+		checkErrInTest(err, "can not read sample file", test)
+
+		var schema rdl.Schema
+		err = json.Unmarshal(data, &schema)
+		checkErrInTest(err, "unmarshal sample data fail", test)
+
+		swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+		checkErrInTest(err, "cannot generate swagger", test)
+		names = append(names, string(schema.Name))
+		docs = append(docs, swaggerData)
+	}
+
+	bundle, err := bundleSwagger("product", "", names, docs)
+	checkErrInTest(err, "cannot bundle swagger", test)
+	j, err := json.MarshalIndent(bundle, "", "    ")
+	checkErrInTest(err, "cannot marshal swagger", test)
+
+	expectedSampleSwagger, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/bundle_swagger.json")
+	checkErrInTest(err, "cannot read swagger json file", test)
+
+	if (string(j) != string(expectedSampleSwagger)) {
+		test.Errorf("bundled swagger json not generated as expected, real: \n%s\n, expected: \n%s\n",
+			string(j), string(expectedSampleSwagger))
+	}
+}
+
+func TestBundleConflict(test *testing.T) {
+	var names []string
+	var docs []*SwaggerDoc
+	for _, name := range []string{"deprecation", "headers"} {
+		data, err := ioutil.ReadFile("../../testdata/rdl-gen-parsec-swagger/" + name + ".json")
+		checkErrInTest(err, "can not read sample file", test)
+
+		var schema rdl.Schema
+		err = json.Unmarshal(data, &schema)
+		checkErrInTest(err, "unmarshal sample data fail", test)
+
+		swaggerData, err := swagger(&schema, false, "", "", "", "Authorization", false, true, false, "")
+		checkErrInTest(err, "cannot generate swagger", test)
+		names = append(names, string(schema.Name))
+		docs = append(docs, swaggerData)
+	}
+
+	_, err := bundleSwagger("product", "", names, docs)
+	if err == nil || err.Error() != "definition Item of headers conflicts with the one of deprecation" {
+		test.Errorf("conflicting definitions not detected as expected, real: %v", err)
+	}
+
+	// the same schema twice defines every operation twice
+	_, err = bundleSwagger("product", "", []string{"headers", "headers"}, []*SwaggerDoc{docs[1], docs[1]})
+	if err == nil || !strings.Contains(err.Error(), "of headers is already defined by headers") {
+		test.Errorf("duplicate operations not detected as expected, real: %v", err)
+	}
+
+	// the same operations under another base path keep their operationIds, which name their fixtures
+	moved := *docs[1]
+	moved.BasePath = "/moved"
+	_, err = bundleSwagger("product", "", []string{"headers", "moved"}, []*SwaggerDoc{docs[1], &moved})
+	if err == nil || !strings.Contains(err.Error(), "of moved is already used by headers") {
+		test.Errorf("duplicate operationIds not detected as expected, real: %v", err)
+	}
+}
+
 func checkErrInTest(err error, msg string, test *testing.T) {
 	if err != nil {
 		test.Error(msg)
@@ -4,7 +4,7 @@
 package main
 
 //
-// export and RDL schema to Swagger 2.0 (http://swagger.io)
+// export and RDL schema to Swagger 2.0 (http://swagger.io) or OpenAPI 3.1 (https://spec.openapis.org/oas/v3.1.0)
 //
 
 import (
@@ -14,15 +14,19 @@ import (
 	"github.com/ardielle/ardielle-go/rdl"
 	"github.com/iancoleman/orderedmap"
 	"github.com/yahoo/parsec-rdl-gen/utils"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 const (
-	ExampleAnnotationKey = "x_example"
+	ExampleAnnotationKey       = "x_example"
+	DiscriminatorAnnotationKey = "x_discriminator"
+	AuthSecurityName           = "auth"
 )
 
 func main() {
@@ -32,17 +36,65 @@ func main() {
 	scheme := flag.String("c", "", "Scheme")
 	finalName := flag.String("f", "", "FinalName of jar package, will be a part of path in basePath")
 	apiHost := flag.String("t", "", "The host serving the API")
+	openAPIString := flag.String("openapi", "false", "Generate OpenAPI 3.1 document instead of Swagger 2.0")
+	authHeader := flag.String("ah", "Authorization", "The header carrying the credentials of resources that require authentication")
+	genUsingPathString := flag.String("p", "true", "Generate operationId using path, like the handler methods of parsec-java-server")
+	allOfString := flag.String("allof", "false", "Generate derived structs as allOf their base struct and own properties instead of flattening the inherited fields")
+	examplesString := flag.String("examples", "false", "Generate example request and response bodies and parameters from the types")
+	fixtures := flag.String("fixtures", "", "Directory to write the example request and response bodies to as JSON files, implies -examples")
+	extensions := flag.String("x", "", "Comma separated x_ annotations to pass through as vendor extensions, all by default; names prefixed with '-' are left out")
+	format := flag.String("format", JSONFormat, "Output format, json or yaml")
+	bundle := flag.String("bundle", "", "Merge the schemas given as files (or concatenated on stdin) into one document with this name")
+	title := flag.String("title", "", "Title of the API, \"The <schema name> API\" by default")
+	terms := flag.String("terms", "", "URL of the terms of service of the API")
+	contactName := flag.String("contact-name", "", "Name of the contact for the API")
+	contactURL := flag.String("contact-url", "", "URL of the contact for the API")
+	contactEmail := flag.String("contact-email", "", "Email address of the contact for the API")
+	license := flag.String("license", "", "Name of the license of the API")
+	licenseURL := flag.String("license-url", "", "URL of the license of the API")
+	servers := flag.String("servers", "", "Comma separated server URLs of the OpenAPI document, derived from the scheme, host and base path by default")
 	flag.Parse()
 
 	genParsecError, err := strconv.ParseBool(*genParsecErrorString)
 	checkErr(err)
+	openAPI, err := strconv.ParseBool(*openAPIString)
+	checkErr(err)
+	allOf, err := strconv.ParseBool(*allOfString)
+	checkErr(err)
+	genUsingPath, err := strconv.ParseBool(*genUsingPathString)
+	checkErr(err)
+	examples, err := strconv.ParseBool(*examplesString)
+	checkErr(err)
+	if *format != JSONFormat && *format != YAMLFormat {
+		checkErr(fmt.Errorf("unsupported format: %s", *format))
+	}
+	info := &SwaggerInfo{Title: *title, TermsOfService: *terms}
+	if *contactName != "" || *contactURL != "" || *contactEmail != "" {
+		info.Contact = &SwaggerContact{*contactName, *contactURL, *contactEmail}
+	}
+	if *license != "" {
+		info.License = &SwaggerLicense{*license, *licenseURL}
+	}
+	var serverURLs []string
+	for _, url := range strings.Split(*servers, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			serverURLs = append(serverURLs, url)
+		}
+	}
+
+	if *bundle != "" {
+		schemas, err := readSchemas(flag.Args())
+		checkErr(err)
+		checkErr(ExportBundleToSwagger(schemas, *bundle, *pOutdir, genParsecError, *scheme, *finalName, *apiHost, openAPI, *authHeader, allOf, genUsingPath, examples, *fixtures, *extensions, *format, info, serverURLs))
+		os.Exit(0)
+	}
 
 	data, err := ioutil.ReadAll(os.Stdin)
 	if err == nil {
 		var schema rdl.Schema
 		err = json.Unmarshal(data, &schema)
 		if err == nil {
-			ExportToSwagger(&schema, *pOutdir, genParsecError, *scheme, *finalName, *apiHost)
+			ExportToSwagger(&schema, *pOutdir, genParsecError, *scheme, *finalName, *apiHost, openAPI, *authHeader, allOf, genUsingPath, examples, *fixtures, *extensions, *format, info, serverURLs)
 			os.Exit(0)
 		}
 	}
@@ -57,15 +109,94 @@ func checkErr(err error) {
 	}
 }
 
-// ExportToSwagger exports the RDL schema to Swagger 2.0 format,
+// readSchemas reads the JSON schemas from the given files, or the schemas concatenated on stdin if there are none.
+func readSchemas(files []string) ([]*rdl.Schema, error) {
+	var schemas []*rdl.Schema
+	if len(files) == 0 {
+		dec := json.NewDecoder(os.Stdin)
+		for {
+			schema := new(rdl.Schema)
+			err := dec.Decode(schema)
+			if err == io.EOF {
+				return schemas, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			schemas = append(schemas, schema)
+		}
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		schema := new(rdl.Schema)
+		if err = json.Unmarshal(data, schema); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// ExportToSwagger exports the RDL schema to Swagger 2.0 format (or OpenAPI 3.1 if openAPI is set),
 //   and serves it up on the specified server endpoint is provided, or outputs to stdout otherwise.
+//   The example bodies are also written to the fixtures directory if one is given. The non-empty fields of info
+//   override those derived from the schema, and the servers those derived from the scheme, host and base path.
 func ExportToSwagger(schema *rdl.Schema, outdir string, genParsecError bool, swaggerScheme string, finalName string,
-	apiHost string) error {
-	swaggerData, err := swagger(schema, genParsecError, swaggerScheme, finalName, apiHost)
+	apiHost string, openAPI bool, authHeader string, allOf bool, genUsingPath bool, examples bool, fixtures string, extensions string, format string,
+	info *SwaggerInfo, servers []string) error {
+	swaggerData, err := swagger(schema, genParsecError, swaggerScheme, finalName, apiHost, authHeader, allOf, genUsingPath, examples || fixtures != "", extensions)
+	if err != nil {
+		return err
+	}
+	return outputSwagger(swaggerData, string(schema.Name), outdir, openAPI, fixtures, format, info, servers)
+}
+
+// ExportBundleToSwagger exports the RDL schemas like ExportToSwagger does, merged into one document named after
+//   the bundle. Every schema keeps its own base path, and definitions of the same name must be identical.
+func ExportBundleToSwagger(schemas []*rdl.Schema, bundleName string, outdir string, genParsecError bool, swaggerScheme string, finalName string,
+	apiHost string, openAPI bool, authHeader string, allOf bool, genUsingPath bool, examples bool, fixtures string, extensions string, format string,
+	info *SwaggerInfo, servers []string) error {
+	var names []string
+	var docs []*SwaggerDoc
+	for _, schema := range schemas {
+		swaggerData, err := swagger(schema, genParsecError, swaggerScheme, finalName, apiHost, authHeader, allOf, genUsingPath, examples || fixtures != "", extensions)
+		if err != nil {
+			return err
+		}
+		names = append(names, string(schema.Name))
+		docs = append(docs, swaggerData)
+	}
+	bundle, err := bundleSwagger(bundleName, swaggerBasePath(finalName), names, docs)
 	if err != nil {
 		return err
 	}
-	j, err := json.MarshalIndent(swaggerData, "", "    ")
+	return outputSwagger(bundle, bundleName, outdir, openAPI, fixtures, format, info, servers)
+}
+
+func outputSwagger(swaggerData *SwaggerDoc, name string, outdir string, openAPI bool, fixtures string, format string, info *SwaggerInfo, servers []string) error {
+	addSwaggerInfo(swaggerData.Info, info)
+	if fixtures != "" {
+		if err := writeSwaggerFixtures(swaggerData, fixtures); err != nil {
+			return err
+		}
+	}
+	var doc interface{} = swaggerData
+	ext := "_swagger." + format
+	if openAPI {
+		openAPIData := openAPIDoc(swaggerData)
+		if len(servers) > 0 {
+			openAPIData.Servers = nil
+			for _, url := range servers {
+				openAPIData.Servers = append(openAPIData.Servers, &OpenAPIServer{URL: url})
+			}
+		}
+		doc = openAPIData
+		ext = "_openapi." + format
+	}
+	j, err := marshalSwagger(doc, format)
 	if err != nil {
 		return err
 	}
@@ -76,7 +207,7 @@ func ExportToSwagger(schema *rdl.Schema, outdir string, genParsecError bool, swa
 			fmt.Printf("%s\n", string(j))
 			return nil
 		}
-		out, file, _, err := utils.OutputWriter(outdir, string(schema.Name), "_swagger.json")
+		out, file, _, err := utils.OutputWriter(outdir, name, ext)
 		if err != nil {
 			return err
 		}
@@ -93,23 +224,105 @@ func ExportToSwagger(schema *rdl.Schema, outdir string, genParsecError bool, swa
 	} else {
 		endpoint = "localhost" + outdir
 	}
-	filename := "/rdl-generated.json"
-	if schema.Name != "" {
-		filename = "/" + string(schema.Name) + ".json"
+	filename := "/rdl-generated." + format
+	if name != "" {
+		filename = "/" + name + "." + format
+	}
+	contentType := "application/json"
+	if format == YAMLFormat {
+		contentType = "application/yaml"
 	}
 	fmt.Println("Serving Swagger resource here: 'http://" + endpoint + filename + "'. Ctrl-C to stop.")
 	http.HandleFunc(filename, func(w http.ResponseWriter, r *http.Request) {
 		h := w.Header()
 		h["Access-Control-Allow-Origin"] = []string{"*"}
-		h["Content-Type"] = []string{"application/json"}
+		h["Content-Type"] = []string{contentType}
 		w.WriteHeader(200)
 		fmt.Fprint(w, string(j))
 	})
 	return http.ListenAndServe(outdir, nil)
 }
 
-func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, finalName string, apiHost string) (*SwaggerDoc, error) {
+// addSwaggerInfo overrides the info derived from the schema with the non-empty fields of the given info.
+func addSwaggerInfo(dst *SwaggerInfo, src *SwaggerInfo) {
+	if src == nil {
+		return
+	}
+	if src.Title != "" {
+		dst.Title = src.Title
+	}
+	if src.Description != "" {
+		dst.Description = src.Description
+	}
+	if src.TermsOfService != "" {
+		dst.TermsOfService = src.TermsOfService
+	}
+	if src.Contact != nil {
+		dst.Contact = src.Contact
+	}
+	if src.License != nil {
+		dst.License = src.License
+	}
+}
+
+// swaggerTags describes the tags of the operations that are named after a type with the comment of that type.
+func swaggerTags(reg rdl.TypeRegistry, paths map[string]map[string]*SwaggerAction) []*SwaggerTag {
+	described := make(map[string]bool)
+	var tags []*SwaggerTag
+	for _, actions := range paths {
+		for _, action := range actions {
+			for _, tag := range action.Tags {
+				t := reg.FindType(rdl.TypeRef(tag))
+				if described[tag] || t == nil {
+					continue
+				}
+				if _, _, comment := rdl.TypeInfo(t); comment != "" {
+					tags = append(tags, &SwaggerTag{tag, comment})
+					described[tag] = true
+				}
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags
+}
+
+// swaggerBasePath is the part of the basePath given by the finalName of the jar package.
+func swaggerBasePath(finalName string) string {
+	if finalName == "" {
+		return ""
+	}
+	if string([]rune(finalName)[0]) != "/" {
+		return "/" + finalName
+	}
+	return finalName
+}
+
+// swaggerOperationIDs returns the operationIds of the resources: the names of their handler methods. The resources
+// that share one, like overloaded handler methods, are named after their paths instead.
+func swaggerOperationIDs(resources []*rdl.Resource, genUsingPath bool) ([]string, error) {
+	ids := make([]string, len(resources))
+	count := make(map[string]int)
+	for i, r := range resources {
+		ids[i] = utils.JavaMethodName(r, genUsingPath)
+		count[ids[i]]++
+	}
+	owners := make(map[string]*rdl.Resource)
+	for i, r := range resources {
+		if count[ids[i]] > 1 {
+			ids[i] = utils.JavaMethodName(r, true)
+		}
+		if owner, ok := owners[ids[i]]; ok {
+			return nil, fmt.Errorf("operationId %s of %s %s is already used by %s %s", ids[i], r.Method, r.Path, owner.Method, owner.Path)
+		}
+		owners[ids[i]] = r
+	}
+	return ids, nil
+}
+
+func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, finalName string, apiHost string, authHeader string, allOf bool, genUsingPath bool, examples bool, extensions string) (*SwaggerDoc, error) {
 	reg := rdl.NewTypeRegistry(schema)
+	ext := newExtensionFilter(extensions)
 	swag := new(SwaggerDoc)
 	swag.Swagger = "2.0"
 	swag.Schemes = []string{}
@@ -120,13 +333,7 @@ func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, fina
 		swag.Schemes = append(swag.Schemes, swaggerScheme)
 	}
 
-	if finalName != "" {
-		if string([]rune(finalName)[0]) != "/" {
-			swag.BasePath = "/" + finalName
-		} else {
-			swag.BasePath = finalName
-		}
-	}
+	swag.BasePath = swaggerBasePath(finalName)
 
 	if apiHost != "" {
 		swag.Host = apiHost
@@ -149,8 +356,12 @@ func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, fina
 		swag.Info.Description = schema.Comment
 	}
 	if len(schema.Resources) > 0 {
+		operationIDs, err := swaggerOperationIDs(schema.Resources, genUsingPath)
+		if err != nil {
+			return nil, err
+		}
 		paths := make(map[string]map[string]*SwaggerAction)
-		for _, r := range schema.Resources {
+		for i, r := range schema.Resources {
 			path := r.Path
 			actions, ok := paths[path]
 			if !ok {
@@ -163,6 +374,7 @@ func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, fina
 				action = new(SwaggerAction)
 			}
 			action.Summary = r.Comment
+			action.OperationID = operationIDs[i]
 			var tags []string
 			for e := range r.Annotations {
 				str := string(e)
@@ -174,11 +386,22 @@ func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, fina
 				tags = append(tags, string(r.Type))
 			}
 			action.Tags = tags
+			action.Extensions = ext.extensions(r.Annotations)
+			if utils.IsDeprecated(r.Annotations) {
+				action.Deprecated = true
+				action.Description = utils.DeprecationNote(r.Annotations)
+			}
 			action.Produces = []string{"application/json"}
+			if len(r.Produces) > 0 {
+				action.Produces = r.Produces
+			}
 			var ins []*SwaggerParameter
 			if len(r.Inputs) > 0 {
 				if r.Method == "POST" || r.Method == "PUT" {
 					action.Consumes = []string{"application/json"}
+					if len(r.Consumes) > 0 {
+						action.Consumes = r.Consumes
+					}
 				}
 				for _, in := range r.Inputs {
 					param := new(SwaggerParameter)
@@ -200,51 +423,67 @@ func swagger(schema *rdl.Schema, genParsecError bool, swaggerScheme string, fina
 					} else {
 						param.In = "body"
 					}
-					ptype, pformat, ref := makeSwaggerTypeRef(reg, in.Type)
-					param.Type = ptype
-					param.Format = pformat
-					param.Schema = ref
+					if param.In == "body" {
+						param.Type, param.Format, param.Schema = makeSwaggerTypeRef(reg, in.Type)
+					} else {
+						setSwaggerParamType(reg, param, in.Type)
+					}
 					if in.Default != nil {
 						param.Default = in.Default
 					}
 					if in.Annotations[ExampleAnnotationKey] != "" {
 						param.Example = in.Annotations[ExampleAnnotationKey]
+					} else if examples {
+						param.Example = exampleValue(reg, in.Type, "", "", make(map[rdl.TypeRef]bool))
 					}
+					annotations := inlineAnnotations(reg, in.Type, in.Annotations)
+					param.Extensions = ext.extensions(annotations)
+					param.XDeprecated = utils.IsDeprecated(annotations)
+					param.Description = deprecatedDescription(param.Description, annotations)
 					ins = append(ins, param)
 				}
 				action.Parameters = ins
 			}
 			responses := make(map[string]*SwaggerResponse)
 			expected := r.Expected
-			addSwaggerResponse(reg, responses, r.Type, expected, "")
+			addSwaggerResponse(reg, responses, r.Type, expected, "", r.Outputs, action.Produces, examples)
 			if len(r.Alternatives) > 0 {
 				for _, alt := range r.Alternatives {
-					addSwaggerResponse(reg, responses, r.Type, alt, "")
+					addSwaggerResponse(reg, responses, r.Type, alt, "", r.Outputs, action.Produces, examples)
 				}
 			}
 			if len(r.Exceptions) > 0 {
 				for sym, errdef := range r.Exceptions {
 					errType := errdef.Type //xxx
-					addSwaggerResponse(reg, responses, rdl.TypeRef(errType), sym, errdef.Comment)
+					addSwaggerResponse(reg, responses, rdl.TypeRef(errType), sym, errdef.Comment, nil, action.Produces, examples)
 				}
 			}
 			action.Responses = responses
 			//responses -> r.expected and r.exceptions
-			//security -> r.auth
-			//r.outputs?
+			if r.Auth != nil && (r.Auth.Authenticate || r.Auth.Action != "") {
+				action.Security = []map[string][]string{{AuthSecurityName: {}}}
+				if r.Auth.Action != "" {
+					action.XAuthorize = &SwaggerAuthorize{r.Auth.Action, r.Auth.Resource, r.Auth.Domain}
+				}
+				if swag.SecurityDefinitions == nil {
+					swag.SecurityDefinitions = map[string]*SwaggerSecurityScheme{
+						AuthSecurityName: {Type: "apiKey", In: "header", Name: authHeader},
+					}
+				}
+			}
 			//action.description?
-			//action.operationId IGNORE
 
 			actions[meth] = action
 			paths[path] = actions
 		}
 		swag.Paths = paths
+		swag.Tags = swaggerTags(reg, paths)
 	}
 
 	//always generate Definitions for ResourceError
 	defs := make(map[string]*SwaggerType)
 	for _, t := range schema.Types {
-		ref := makeSwaggerTypeDef(reg, t)
+		ref := makeSwaggerTypeDef(reg, t, allOf, ext)
 		if ref != nil {
 			tName, _, _ := rdl.TypeInfo(t)
 			defs[string(tName)] = ref
@@ -321,9 +560,11 @@ func addParsecError(defs map[string]*SwaggerType) {
 	defs["ParsecErrorDetail"] = errDetailProp
 }
 
-func addSwaggerResponse(reg rdl.TypeRegistry, responses map[string]*SwaggerResponse, errType rdl.TypeRef, sym string, errComment string) {
+func addSwaggerResponse(reg rdl.TypeRegistry, responses map[string]*SwaggerResponse, errType rdl.TypeRef, sym string, errComment string, outputs []*rdl.ResourceOutput,
+	produces []string, examples bool) {
 	code := rdl.StatusCode(sym)
 	var schema *SwaggerType
+	var bodyExamples map[string]interface{}
 	if sym != "NO_CONTENT" {
 		ptype, pformat, pswaggerType := makeSwaggerTypeRef(reg, errType)
 		schema = new(SwaggerType)
@@ -332,20 +573,37 @@ func addSwaggerResponse(reg rdl.TypeRegistry, responses map[string]*SwaggerRespo
 		if pswaggerType != nil {
 			schema.Ref = pswaggerType.Ref
 		}
+		if examples {
+			if example := exampleValue(reg, errType, "", "", make(map[rdl.TypeRef]bool)); example != nil {
+				bodyExamples = make(map[string]interface{})
+				for _, mt := range produces {
+					bodyExamples[mt] = example
+				}
+			}
+		}
 	}
 	description := rdl.StatusMessage(sym)
 	if errComment != "" {
 		description += " - " + errComment
 	}
-	responses[code] = &SwaggerResponse{description, schema}
+	var headers map[string]*SwaggerHeader
+	if len(outputs) > 0 {
+		headers = make(map[string]*SwaggerHeader)
+		for _, out := range outputs {
+			header := &SwaggerHeader{Description: out.Comment}
+			setSwaggerHeaderType(reg, header, out.Type)
+			headers[out.Header] = header
+		}
+	}
+	responses[code] = &SwaggerResponse{description, schema, headers, bodyExamples}
 }
 
 func makeSwaggerTypeRef(reg rdl.TypeRegistry, itemTypeName rdl.TypeRef) (string, string, *SwaggerType) {
 	itype := string(itemTypeName)
 	switch reg.FindBaseType(itemTypeName) {
-	case rdl.BaseTypeInt8:
+	case rdl.BaseTypeBytes:
 		return "string", "byte", nil
-	case rdl.BaseTypeInt16, rdl.BaseTypeInt32, rdl.BaseTypeInt64:
+	case rdl.BaseTypeInt8, rdl.BaseTypeInt16, rdl.BaseTypeInt32, rdl.BaseTypeInt64:
 		return "integer", strings.ToLower(itype), nil
 	case rdl.BaseTypeFloat32:
 		return "number", "float", nil
@@ -366,7 +624,263 @@ func makeSwaggerTypeRef(reg rdl.TypeRegistry, itemTypeName rdl.TypeRef) (string,
 	}
 }
 
-func makeSwaggerTypeDef(reg rdl.TypeRegistry, t *rdl.Type) *SwaggerType {
+// setSwaggerParamType describes the type of a path, query or header parameter inline, since only body parameters can
+// reference a definition: arrays get their items and how they are serialized (repeated query parameters, comma
+// separated values otherwise), enums get their symbols.
+func setSwaggerParamType(reg rdl.TypeRegistry, param *SwaggerParameter, typeRef rdl.TypeRef) {
+	switch reg.FindBaseType(typeRef) {
+	case rdl.BaseTypeArray:
+		param.Type = "array"
+		items := new(SwaggerType)
+		setSwaggerItemsType(reg, items, arrayItems(reg, typeRef))
+		param.Items = items
+		if param.In == "query" {
+			param.CollectionFormat = "multi"
+		} else {
+			param.CollectionFormat = "csv"
+		}
+	case rdl.BaseTypeEnum:
+		param.Type = "string"
+		param.Enum = enumSymbols(reg, typeRef)
+	default:
+		param.Type, param.Format, param.Schema = makeSwaggerTypeRef(reg, typeRef)
+	}
+}
+
+// setSwaggerHeaderType describes a response header like a header parameter: headers cannot reference a definition.
+func setSwaggerHeaderType(reg rdl.TypeRegistry, header *SwaggerHeader, typeRef rdl.TypeRef) {
+	switch reg.FindBaseType(typeRef) {
+	case rdl.BaseTypeArray:
+		header.Type = "array"
+		items := new(SwaggerType)
+		setSwaggerItemsType(reg, items, arrayItems(reg, typeRef))
+		header.Items = items
+		header.CollectionFormat = "csv"
+	case rdl.BaseTypeEnum:
+		header.Type = "string"
+		header.Enum = enumSymbols(reg, typeRef)
+	default:
+		var ref *SwaggerType
+		header.Type, header.Format, ref = makeSwaggerTypeRef(reg, typeRef)
+		if ref != nil {
+			// structured values are sent in their string form
+			header.Type = "string"
+		}
+	}
+}
+
+// setSwaggerItemsType describes the items of an array parameter, which are primitive values.
+func setSwaggerItemsType(reg rdl.TypeRegistry, items *SwaggerType, typeRef rdl.TypeRef) {
+	if reg.FindBaseType(typeRef) == rdl.BaseTypeEnum {
+		items.Type = "string"
+		items.Enum = enumSymbols(reg, typeRef)
+		return
+	}
+	var ref *SwaggerType
+	items.Type, items.Format, ref = makeSwaggerTypeRef(reg, typeRef)
+	if ref != nil {
+		// structured items cannot be passed as a parameter but in their string form
+		items.Type = "string"
+	}
+}
+
+// arrayItems returns the items of an array type, Any if the type does not say.
+func arrayItems(reg rdl.TypeRegistry, typeRef rdl.TypeRef) rdl.TypeRef {
+	for t := reg.FindType(typeRef); t != nil; {
+		if t.Variant == rdl.TypeVariantArrayTypeDef && t.ArrayTypeDef.Items != "" {
+			return t.ArrayTypeDef.Items
+		}
+		tName, super, _ := rdl.TypeInfo(t)
+		if rdl.TypeRef(tName) == super {
+			break
+		}
+		t = reg.FindType(super)
+	}
+	return "Any"
+}
+
+// enumSymbols returns the symbols of an enum type.
+func enumSymbols(reg rdl.TypeRegistry, typeRef rdl.TypeRef) []string {
+	var symbols []string
+	for t := reg.FindType(typeRef); t != nil; {
+		if t.Variant == rdl.TypeVariantEnumTypeDef {
+			for _, el := range t.EnumTypeDef.Elements {
+				symbols = append(symbols, string(el.Symbol))
+			}
+			return symbols
+		}
+		tName, super, _ := rdl.TypeInfo(t)
+		if rdl.TypeRef(tName) == super {
+			break
+		}
+		t = reg.FindType(super)
+	}
+	return symbols
+}
+
+// makeSwaggerTypeSchema describes a value of the given type, using the inline items and keys of a struct field if set.
+// Types that have a definition (structs, enums, unions and named arrays and maps) are referenced, every other type
+// is described inline along with the facets of its typedef chain.
+func makeSwaggerTypeSchema(reg rdl.TypeRegistry, typeRef rdl.TypeRef, items rdl.TypeRef, keys rdl.TypeRef) *SwaggerType {
+	st := new(SwaggerType)
+	t := reg.FindType(typeRef)
+	bt := reg.FindBaseType(typeRef)
+	switch bt {
+	case rdl.BaseTypeStruct, rdl.BaseTypeEnum, rdl.BaseTypeUnion:
+		st.Ref = "#/definitions/" + string(typeRef)
+		return st
+	case rdl.BaseTypeArray, rdl.BaseTypeMap:
+		if !reg.IsBaseTypeName(typeRef) && items == "" && keys == "" {
+			st.Ref = "#/definitions/" + string(typeRef)
+			return st
+		}
+	}
+	switch bt {
+	case rdl.BaseTypeArray:
+		st.Type = "array"
+		if items == "" && t != nil && t.Variant == rdl.TypeVariantArrayTypeDef {
+			items = t.ArrayTypeDef.Items
+		}
+		if items != "" && items != "Any" {
+			st.Items = makeSwaggerTypeSchema(reg, items, "", "")
+		}
+	case rdl.BaseTypeMap:
+		st.Type = "object"
+		if t != nil && t.Variant == rdl.TypeVariantMapTypeDef {
+			if items == "" {
+				items = t.MapTypeDef.Items
+			}
+			if keys == "" {
+				keys = t.MapTypeDef.Keys
+			}
+		}
+		if items != "" && items != "Any" {
+			st.AdditionalProperties = makeSwaggerTypeSchema(reg, items, "", "")
+		}
+		if keys != "" && keys != "String" {
+			// JSON object keys are always strings, the constraints of the key type become propertyNames in OpenAPI 3.1
+			st.XPropertyNames = makeSwaggerTypeSchema(reg, keys, "", "")
+		}
+	case rdl.BaseTypeAny:
+		return st
+	default:
+		st.Type, st.Format, _ = makeSwaggerTypeRef(reg, rdl.TypeRef(bt.String()))
+	}
+	addSwaggerFacets(reg, st, typeRef)
+	return st
+}
+
+// addSwaggerFacets copies the patterns, values, sizes and bounds of a typedef chain onto the schema,
+// the nearest typedef wins.
+func addSwaggerFacets(reg rdl.TypeRegistry, st *SwaggerType, typeRef rdl.TypeRef) {
+	t := reg.FindType(typeRef)
+	for visited := make(map[rdl.TypeName]bool); t != nil; {
+		tName, tType, _ := rdl.TypeInfo(t)
+		if visited[tName] || reg.IsBaseTypeName(rdl.TypeRef(tName)) {
+			break
+		}
+		visited[tName] = true
+		switch t.Variant {
+		case rdl.TypeVariantStringTypeDef:
+			typedef := t.StringTypeDef
+			if st.Pattern == "" {
+				st.Pattern = typedef.Pattern
+			}
+			if st.Enum == nil && len(typedef.Values) > 0 {
+				st.Enum = typedef.Values
+			}
+			setSwaggerSize(&st.MinLength, &st.MaxLength, nil, typedef.MinSize, typedef.MaxSize)
+		case rdl.TypeVariantNumberTypeDef:
+			typedef := t.NumberTypeDef
+			if st.Minimum == nil {
+				st.Minimum = swaggerNumber(typedef.Min)
+			}
+			if st.Maximum == nil {
+				st.Maximum = swaggerNumber(typedef.Max)
+			}
+		case rdl.TypeVariantArrayTypeDef:
+			typedef := t.ArrayTypeDef
+			setSwaggerSize(&st.MinItems, &st.MaxItems, typedef.Size, typedef.MinSize, typedef.MaxSize)
+		case rdl.TypeVariantMapTypeDef:
+			typedef := t.MapTypeDef
+			setSwaggerSize(&st.MinProperties, &st.MaxProperties, typedef.Size, typedef.MinSize, typedef.MaxSize)
+		}
+		t = reg.FindType(tType)
+	}
+}
+
+// setSwaggerSize sets the bounds that are not set yet, an exact size sets both of them
+func setSwaggerSize(min **int32, max **int32, size *int32, minSize *int32, maxSize *int32) {
+	if size != nil {
+		minSize = size
+		maxSize = size
+	}
+	if *min == nil {
+		*min = minSize
+	}
+	if *max == nil {
+		*max = maxSize
+	}
+}
+
+func swaggerNumber(n *rdl.Number) interface{} {
+	if n == nil {
+		return nil
+	}
+	switch n.Variant {
+	case rdl.NumberVariantInt8:
+		return *n.Int8
+	case rdl.NumberVariantInt16:
+		return *n.Int16
+	case rdl.NumberVariantInt32:
+		return *n.Int32
+	case rdl.NumberVariantInt64:
+		return *n.Int64
+	case rdl.NumberVariantFloat32:
+		return *n.Float32
+	case rdl.NumberVariantFloat64:
+		return *n.Float64
+	}
+	return nil
+}
+
+// addSwaggerExample sets the x_example of a field on its schema, or on the items of an array or map field.
+// Unconstrained strings, numbers and booleans without an example get the zero value of their type.
+func addSwaggerExample(st *SwaggerType, example string) {
+	if st == nil || st.Ref != "" {
+		return
+	}
+	switch st.Type {
+	case "array":
+		addSwaggerExample(st.Items, example)
+	case "object":
+		addSwaggerExample(st.AdditionalProperties, example)
+	case "string":
+		if example != "" || (st.Format == "" && st.Pattern == "" && st.Enum == nil && st.MinLength == nil) {
+			st.Example = example
+		}
+	case "integer":
+		if v, err := strconv.ParseInt(example, 10, 64); err == nil {
+			st.Example = v
+		} else if st.Minimum == nil && st.Maximum == nil {
+			st.Example = 0
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(example, 64); err == nil {
+			st.Example = v
+		} else if st.Minimum == nil && st.Maximum == nil {
+			st.Example = 0
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(example); err == nil {
+			st.Example = v
+		} else {
+			st.Example = false
+		}
+	}
+}
+
+func makeSwaggerTypeDef(reg rdl.TypeRegistry, t *rdl.Type, allOf bool, ext *extensionFilter) *SwaggerType {
 	st := new(SwaggerType)
 	bt := reg.BaseType(t)
 	switch t.Variant {
@@ -376,144 +890,48 @@ func makeSwaggerTypeDef(reg rdl.TypeRegistry, t *rdl.Type) *SwaggerType {
 		props := orderedmap.New()
 		var required []string
 		fields := utils.FlattenedFields(reg, t)
+		derived := allOf && typedef.Type != "Struct"
+		if derived {
+			// the inherited fields are described by the base struct's definition
+			fields = typedef.Fields
+		}
 		if len(fields) > 0 {
 			for _, f := range fields {
 				if !f.Optional {
 					required = append(required, string(f.Name))
 				}
-				ft := reg.FindType(f.Type)
-				fbt := reg.BaseType(ft)
-				prop := new(SwaggerType)
+				prop := makeSwaggerTypeSchema(reg, f.Type, f.Items, f.Keys)
 				prop.Description = f.Comment
-				switch fbt {
-				case rdl.BaseTypeArray:
-					prop.Type = "array"
-					if ft.Variant == rdl.TypeVariantArrayTypeDef && f.Items == "" {
-						f.Items = ft.ArrayTypeDef.Items
-					}
-					if f.Items != "" {
-						fItems := string(f.Items)
-						items := new(SwaggerType)
-						switch f.Items {
-						case "String":
-							items.Type = strings.ToLower(fItems)
-							items.Example = f.Annotations[ExampleAnnotationKey]
-						case "Int32", "Int64", "Int16":
-							items.Type = "integer"
-							items.Format = strings.ToLower(fItems)
-							if example, err := strconv.Atoi(f.Annotations[ExampleAnnotationKey]); err == nil {
-								items.Example = example
-							} else {
-								items.Example = 0
-							}
-						case "Bool":
-							items.Type = "boolean"
-							if example, err := strconv.ParseBool(f.Annotations[ExampleAnnotationKey]); err == nil {
-								items.Example = example
-							} else {
-								items.Example = false
-							}
-						default:
-							items.Ref = "#/definitions/" + fItems
-						}
-						prop.Items = items
-					}
-				case rdl.BaseTypeString:
-					prop.Type = strings.ToLower(fbt.String())
-					prop.Example = f.Annotations[ExampleAnnotationKey]
-				case rdl.BaseTypeInt32, rdl.BaseTypeInt64, rdl.BaseTypeInt16:
-					prop.Type = "integer"
-					prop.Format = strings.ToLower(fbt.String())
-					if example, err := strconv.Atoi(f.Annotations[ExampleAnnotationKey]); err == nil {
-						prop.Example = example
-					} else {
-						prop.Example = 0
-					}
-				case rdl.BaseTypeBool:
-					prop.Type = "boolean"
-					if example, err := strconv.ParseBool(f.Annotations[ExampleAnnotationKey]); err == nil {
-						prop.Example = example
-					} else {
-						prop.Example = false
-					}
-				case rdl.BaseTypeEnum, rdl.BaseTypeStruct:
-					prop.Ref = "#/definitions/" + string(f.Type)
-				case rdl.BaseTypeMap:
-					prop.Type = "object"
-					if f.Items != "" {
-						fItems := string(f.Items)
-						items := new(SwaggerType)
-						switch f.Items {
-						case "String":
-							items.Type = strings.ToLower(fItems)
-							items.Example = f.Annotations[ExampleAnnotationKey]
-						case "Int32", "Int64", "Int16":
-							items.Type = "integer"
-							items.Format = strings.ToLower(fItems)
-							if example, err := strconv.Atoi(f.Annotations[ExampleAnnotationKey]); err == nil {
-								items.Example = example
-							} else {
-								items.Example = 0
-							}
-						case "Bool":
-							items.Type = "boolean"
-							if example, err := strconv.ParseBool(f.Annotations[ExampleAnnotationKey]); err == nil {
-								items.Example = example
-							} else {
-								items.Example = false
-							}
-						default:
-							items.Ref = "#/definitions/" + fItems
-						}
-						prop.AdditionalProperties = items
-					}
-				default:
-					prop.Type = "_" + string(f.Type) + "_" //!
-					prop.Example = f.Annotations[ExampleAnnotationKey]
-				}
+				addSwaggerExample(prop, f.Annotations[ExampleAnnotationKey])
+				annotations := inlineAnnotations(reg, f.Type, f.Annotations)
+				prop.Extensions = ext.extensions(annotations)
+				prop.XDeprecated = utils.IsDeprecated(annotations)
+				prop.Description = deprecatedDescription(prop.Description, annotations)
 				props.Set(string(f.Name), prop)
 			}
 		}
-		st.Properties = props
+		own := st
+		if derived {
+			own = new(SwaggerType)
+			st.AllOf = []*SwaggerType{{Ref: "#/definitions/" + string(typedef.Type)}, own}
+		}
+		own.Properties = props
 		if len(required) > 0 {
-			st.Required = required
+			own.Required = required
 		}
+		st.Extensions = ext.extensions(typedef.Annotations)
 	case rdl.TypeVariantArrayTypeDef:
 		typedef := t.ArrayTypeDef
-		st.Type = strings.ToLower(bt.String())
-		if typedef.Items != "Any" {
-			tItems := string(typedef.Items)
-			items := new(SwaggerType)
-			switch reg.FindBaseType(typedef.Items) {
-			case rdl.BaseTypeString:
-				items.Type = strings.ToLower(tItems)
-			case rdl.BaseTypeInt32, rdl.BaseTypeInt64, rdl.BaseTypeInt16:
-				items.Type = "integer"
-				items.Format = strings.ToLower(tItems)
-			case rdl.BaseTypeBool:
-				items.Type = "boolean"
-			default:
-				items.Ref = "#/definitions/" + tItems
-			}
-			st.Items = items
-		}
+		st = makeSwaggerTypeSchema(reg, rdl.TypeRef(bt.String()), typedef.Items, "")
+		addSwaggerFacets(reg, st, rdl.TypeRef(typedef.Name))
+		st.Description = typedef.Comment
+		st.Extensions = ext.extensions(typedef.Annotations)
 	case rdl.TypeVariantMapTypeDef:
 		typedef := t.MapTypeDef
-		st.Type = "object"
-		if typedef.Items != "Any" {
-			tItems := string(typedef.Items)
-			items := new(SwaggerType)
-			switch reg.FindBaseType(typedef.Items) {
-			case rdl.BaseTypeString:
-				items.Type = strings.ToLower(tItems)
-			case rdl.BaseTypeInt32, rdl.BaseTypeInt64, rdl.BaseTypeInt16:
-				items.Type = "integer"
-				items.Format = strings.ToLower(tItems)
-			default:
-				items.Ref = "#/definitions/" + tItems
-			}
-			st.AdditionalProperties = items
-		}
+		st = makeSwaggerTypeSchema(reg, rdl.TypeRef(bt.String()), typedef.Items, typedef.Keys)
+		addSwaggerFacets(reg, st, rdl.TypeRef(typedef.Name))
+		st.Description = typedef.Comment
+		st.Extensions = ext.extensions(typedef.Annotations)
 	case rdl.TypeVariantEnumTypeDef:
 		typedef := t.EnumTypeDef
 		var tmp []string
@@ -522,30 +940,105 @@ func makeSwaggerTypeDef(reg rdl.TypeRegistry, t *rdl.Type) *SwaggerType {
 		}
 		st.Enum = tmp
 		st.Type = "string"
+		st.Extensions = ext.extensions(typedef.Annotations)
 	case rdl.TypeVariantUnionTypeDef:
+		// Swagger 2.0 has no oneOf, so the variants go into a vendor extension (oneOf in OpenAPI 3.x)
 		typedef := t.UnionTypeDef
-		fmt.Println("[" + typedef.Name + ": Swagger doesn't support unions]")
+		st.Description = typedef.Comment
+		var variants []*SwaggerType
+		mapping := make(map[string]string)
+		for _, v := range typedef.Variants {
+			vtype, vformat, vref := makeSwaggerTypeRef(reg, v)
+			if vref == nil {
+				vref = new(SwaggerType)
+				vref.Type = vtype
+				vref.Format = vformat
+			} else {
+				mapping[string(v)] = vref.Ref
+			}
+			variants = append(variants, vref)
+		}
+		st.XOneOf = variants
+		if prop := typedef.Annotations[DiscriminatorAnnotationKey]; prop != "" {
+			for _, v := range variantsWithoutField(reg, typedef, prop) {
+				fmt.Fprintf(os.Stderr, "Warning: %s %s of union %s is not a field of its variant %s\n", DiscriminatorAnnotationKey, prop, typedef.Name, v)
+			}
+			st.XDiscriminator = &SwaggerDiscriminator{prop, mapping}
+		}
+		st.Extensions = ext.extensions(typedef.Annotations)
 	default:
 		switch bt {
-		case rdl.BaseTypeString, rdl.BaseTypeInt16, rdl.BaseTypeInt32, rdl.BaseTypeInt64, rdl.BaseTypeFloat32, rdl.BaseTypeFloat64, rdl.BaseTypeBool:
+		case rdl.BaseTypeString, rdl.BaseTypeInt8, rdl.BaseTypeInt16, rdl.BaseTypeInt32, rdl.BaseTypeInt64, rdl.BaseTypeFloat32, rdl.BaseTypeFloat64,
+			rdl.BaseTypeBool, rdl.BaseTypeBytes, rdl.BaseTypeTimestamp, rdl.BaseTypeUUID, rdl.BaseTypeSymbol:
+			// aliases of the base types are described inline where they are used
 			return nil
 		default:
 			panic(fmt.Sprintf("whoops: %v", t))
 		}
 	}
+	if annotations := utils.TypeAnnotations(t); utils.IsDeprecated(annotations) {
+		st.XDeprecated = true
+		st.Description = deprecatedDescription(st.Description, annotations)
+	}
 	return st
 }
 
+// variantsWithoutField lists the struct variants of the union that lack the field, which a discriminator must name
+func variantsWithoutField(reg rdl.TypeRegistry, typedef *rdl.UnionTypeDef, field string) []rdl.TypeRef {
+	var missing []rdl.TypeRef
+	for _, v := range typedef.Variants {
+		if reg.FindBaseType(v) != rdl.BaseTypeStruct {
+			continue
+		}
+		found := false
+		for _, f := range utils.FlattenedFields(reg, reg.FindType(v)) {
+			if string(f.Name) == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// deprecatedDescription appends the deprecation note of a deprecated element to its description.
+func deprecatedDescription(description string, annotations map[rdl.ExtendedAnnotation]string) string {
+	if !utils.IsDeprecated(annotations) {
+		return description
+	}
+	note := utils.DeprecationNote(annotations)
+	if note == "" {
+		note = "Deprecated."
+	} else {
+		note = "Deprecated: " + note
+	}
+	if description == "" {
+		return note
+	}
+	return strings.TrimSuffix(description, ".") + ". " + note
+}
+
 // SwaggerDoc is a representation of the top level object in swagger 2.0
 type SwaggerDoc struct {
-	Swagger     string                               `json:"swagger"`
-	Info        *SwaggerInfo                         `json:"info"`
-	Host        string                               `json:"host,omitempty" rdl:"optional"`
-	BasePath    string                               `json:"basePath"`
-	Schemes     []string                             `json:"schemes"`
-	Paths       map[string]map[string]*SwaggerAction `json:"paths,omitempty"`
-	Security    *map[string][]string                 `json:"security,omitempty"`
-	Definitions map[string]*SwaggerType              `json:"definitions,omitempty"`
+	Swagger             string                               `json:"swagger"`
+	Info                *SwaggerInfo                         `json:"info"`
+	Host                string                               `json:"host,omitempty" rdl:"optional"`
+	BasePath            string                               `json:"basePath"`
+	Schemes             []string                             `json:"schemes"`
+	Paths               map[string]map[string]*SwaggerAction `json:"paths,omitempty"`
+	Security            []map[string][]string                `json:"security,omitempty"`
+	SecurityDefinitions map[string]*SwaggerSecurityScheme    `json:"securityDefinitions,omitempty"`
+	Definitions         map[string]*SwaggerType              `json:"definitions,omitempty"`
+	Tags                []*SwaggerTag                        `json:"tags,omitempty"`
+}
+
+// SwaggerTag - the description of a tag of the operations
+type SwaggerTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
 }
 
 // SwaggerInfo -
@@ -581,27 +1074,61 @@ type SwaggerAction struct {
 	Produces    []string                    `json:"produces,omitempty"`
 	Parameters  []*SwaggerParameter         `json:"parameters,omitempty"`
 	Responses   map[string]*SwaggerResponse `json:"responses,omitempty"`
-	Security    map[string][]string         `json:"security,omitempty"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	XAuthorize  *SwaggerAuthorize           `json:"x-authorize,omitempty"`
+	Extensions  map[string]interface{}      `json:"-"`
+}
+
+// SwaggerSecurityScheme - a security definition (2.0) or security scheme (3.x)
+type SwaggerSecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SwaggerAuthorize - the authorization an RDL resource requires, as the x-authorize vendor extension
+type SwaggerAuthorize struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Domain   string `json:"domain,omitempty"`
 }
 
 // SwaggerParameter -
 type SwaggerParameter struct {
-	Name        string       `json:"name"`
-	In          string       `json:"in"`
-	Schema      *SwaggerType `json:"schema,omitempty"`
-	Type        string       `json:"type,omitempty"`
-	Format      string       `json:"format,omitempty"`
-	Items       *SwaggerType `json:"items,omitempty"`
-	Description string       `json:"description,omitempty"`
-	Required    bool         `json:"required"`
-	Default     interface{}  `json:"default,omitempty"`
-	Example     string       `json:"example,omitempty"`
+	Name             string                 `json:"name"`
+	In               string                 `json:"in"`
+	Schema           *SwaggerType           `json:"schema,omitempty"`
+	Type             string                 `json:"type,omitempty"`
+	Format           string                 `json:"format,omitempty"`
+	Items            *SwaggerType           `json:"items,omitempty"`
+	CollectionFormat string                 `json:"collectionFormat,omitempty"`
+	Enum             []string               `json:"enum,omitempty"`
+	Description      string                 `json:"description,omitempty"`
+	Required         bool                   `json:"required"`
+	Default          interface{}            `json:"default,omitempty"`
+	Example          interface{}            `json:"example,omitempty"`
+	XDeprecated      bool                   `json:"x-deprecated,omitempty"`
+	Extensions       map[string]interface{} `json:"-"`
 }
 
 // SwaggerResponse -
 type SwaggerResponse struct {
-	Description string       `json:"description,omitempty"`
-	Schema      *SwaggerType `json:"schema,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Schema      *SwaggerType              `json:"schema,omitempty"`
+	Headers     map[string]*SwaggerHeader `json:"headers,omitempty"`
+	Examples    map[string]interface{}    `json:"examples,omitempty"`
+}
+
+// SwaggerHeader - a header sent with a response
+type SwaggerHeader struct {
+	Type             string       `json:"type,omitempty"`
+	Format           string       `json:"format,omitempty"`
+	Items            *SwaggerType `json:"items,omitempty"`
+	CollectionFormat string       `json:"collectionFormat,omitempty"`
+	Enum             []string     `json:"enum,omitempty"`
+	Description      string       `json:"description,omitempty"`
 }
 
 // SwaggerType -
@@ -611,12 +1138,37 @@ type SwaggerType struct {
 	Type                 string                 `json:"type,omitempty"`
 	Format               string                 `json:"format,omitempty"`
 	Pattern              string                 `json:"pattern,omitempty"`
+	MinLength            *int32                 `json:"minLength,omitempty"`
+	MaxLength            *int32                 `json:"maxLength,omitempty"`
+	Minimum              interface{}            `json:"minimum,omitempty"`
+	Maximum              interface{}            `json:"maximum,omitempty"`
+	MinItems             *int32                 `json:"minItems,omitempty"`
+	MaxItems             *int32                 `json:"maxItems,omitempty"`
+	MinProperties        *int32                 `json:"minProperties,omitempty"`
+	MaxProperties        *int32                 `json:"maxProperties,omitempty"`
 	Description          string                 `json:"description,omitempty"`
 	Items                *SwaggerType           `json:"items,omitempty"`
 	Ref                  string                 `json:"$ref,omitempty"`
 	Enum                 []string               `json:"enum,omitempty"`
 	AdditionalProperties *SwaggerType           `json:"additionalProperties,omitempty"`
+	PropertyNames        *SwaggerType           `json:"propertyNames,omitempty"`
+	XPropertyNames       *SwaggerType           `json:"x-propertyNames,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
 	Example              interface{}            `json:"example,omitempty"`
+	AllOf                []*SwaggerType         `json:"allOf,omitempty"`
+	OneOf                []*SwaggerType         `json:"oneOf,omitempty"`
+	Discriminator        *SwaggerDiscriminator  `json:"discriminator,omitempty"`
+	XOneOf               []*SwaggerType         `json:"x-oneOf,omitempty"`
+	XDiscriminator       *SwaggerDiscriminator  `json:"x-discriminator,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+	XDeprecated          bool                   `json:"x-deprecated,omitempty"`
+	Extensions           map[string]interface{} `json:"-"`
+}
+
+// SwaggerDiscriminator - the OpenAPI 3.x discriminator object of a union
+type SwaggerDiscriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
 }
 
 /*
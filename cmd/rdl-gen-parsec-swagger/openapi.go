@@ -0,0 +1,276 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// convert a generated Swagger 2.0 document to OpenAPI 3.1 (https://spec.openapis.org/oas/v3.1.0)
+//
+
+import (
+	"strings"
+)
+
+const (
+	OpenAPIVersion          = "3.1.0"
+	SwaggerDefinitionPrefix = "#/definitions/"
+	OpenAPISchemaPrefix     = "#/components/schemas/"
+)
+
+// openAPIDoc builds the OpenAPI 3.1 equivalent of the given Swagger 2.0 document.
+func openAPIDoc(swag *SwaggerDoc) *OpenAPIDoc {
+	doc := new(OpenAPIDoc)
+	doc.OpenAPI = OpenAPIVersion
+	doc.Info = swag.Info
+	doc.Servers = openAPIServers(swag)
+	if len(swag.Paths) > 0 {
+		paths := make(map[string]map[string]*OpenAPIOperation)
+		for path, actions := range swag.Paths {
+			ops := make(map[string]*OpenAPIOperation)
+			for meth, action := range actions {
+				ops[meth] = openAPIOperation(action)
+			}
+			paths[path] = ops
+		}
+		doc.Paths = paths
+	}
+	if len(swag.Definitions) > 0 || len(swag.SecurityDefinitions) > 0 {
+		for _, def := range swag.Definitions {
+			openAPISchema(def)
+		}
+		doc.Components = &OpenAPIComponents{Schemas: swag.Definitions, SecuritySchemes: swag.SecurityDefinitions}
+	}
+	doc.Security = swag.Security
+	doc.Tags = swag.Tags
+	return doc
+}
+
+// openAPIServers derives the server list from the scheme, host and basePath of the Swagger 2.0 document.
+func openAPIServers(swag *SwaggerDoc) []*OpenAPIServer {
+	base := swag.BasePath
+	if swag.Host == "" {
+		return []*OpenAPIServer{{URL: base}}
+	}
+	if len(swag.Schemes) == 0 {
+		return []*OpenAPIServer{{URL: "//" + swag.Host + base}}
+	}
+	var servers []*OpenAPIServer
+	for _, scheme := range swag.Schemes {
+		servers = append(servers, &OpenAPIServer{URL: scheme + "://" + swag.Host + base})
+	}
+	return servers
+}
+
+func openAPIOperation(action *SwaggerAction) *OpenAPIOperation {
+	op := new(OpenAPIOperation)
+	op.Tags = action.Tags
+	op.Summary = action.Summary
+	op.Description = action.Description
+	op.OperationID = action.OperationID
+	op.Security = action.Security
+	op.XAuthorize = action.XAuthorize
+	op.Deprecated = action.Deprecated
+	op.Extensions = action.Extensions
+	for _, param := range action.Parameters {
+		if param.In == "body" {
+			body := new(OpenAPIRequestBody)
+			body.Description = param.Description
+			body.Required = param.Required
+			body.Extensions = param.Extensions
+			body.Content = openAPIContent(action.Consumes, openAPIParamSchema(param), param.Example)
+			op.RequestBody = body
+			continue
+		}
+		p := new(OpenAPIParameter)
+		p.Name = param.Name
+		p.In = param.In
+		p.Description = param.Description
+		p.Required = param.Required || param.In == "path" //path parameters are always required in 3.x
+		p.Schema = openAPIParamSchema(param)
+		p.Example = param.Example
+		p.Deprecated = param.XDeprecated
+		p.Extensions = param.Extensions
+		op.Parameters = append(op.Parameters, p)
+	}
+	if len(action.Responses) > 0 {
+		responses := make(map[string]*OpenAPIResponse)
+		for code, resp := range action.Responses {
+			r := new(OpenAPIResponse)
+			r.Description = resp.Description
+			if resp.Schema != nil {
+				openAPISchema(resp.Schema)
+				var example interface{}
+				for _, v := range resp.Examples {
+					example = v
+				}
+				r.Content = openAPIContent(action.Produces, resp.Schema, example)
+			}
+			if len(resp.Headers) > 0 {
+				r.Headers = make(map[string]*OpenAPIHeader)
+				for name, h := range resp.Headers {
+					r.Headers[name] = &OpenAPIHeader{h.Description, &SwaggerType{Type: h.Type, Format: h.Format, Items: h.Items, Enum: h.Enum}}
+				}
+			}
+			responses[code] = r
+		}
+		op.Responses = responses
+	}
+	return op
+}
+
+// openAPIParamSchema moves the inline type information of a Swagger 2.0 parameter into a schema object.
+func openAPIParamSchema(param *SwaggerParameter) *SwaggerType {
+	schema := param.Schema
+	if schema == nil {
+		schema = new(SwaggerType)
+		schema.Type = param.Type
+		schema.Format = param.Format
+		schema.Items = param.Items
+		schema.Enum = param.Enum
+	}
+	if param.Default != nil {
+		schema.Default = param.Default
+	}
+	openAPISchema(schema)
+	return schema
+}
+
+func openAPIContent(mediaTypes []string, schema *SwaggerType, example interface{}) map[string]*OpenAPIMediaType {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+	content := make(map[string]*OpenAPIMediaType)
+	for _, mt := range mediaTypes {
+		content[mt] = &OpenAPIMediaType{Schema: schema, Example: example}
+	}
+	return content
+}
+
+// openAPISchema rewrites every Swagger 2.0 definition reference in the schema tree to point at components/schemas,
+// and turns the vendor extensions used for unions into their native OpenAPI 3.x keywords.
+func openAPISchema(t *SwaggerType) {
+	if t == nil {
+		return
+	}
+	t.Ref = openAPIRef(t.Ref)
+	if t.XOneOf != nil {
+		t.OneOf = t.XOneOf
+		t.XOneOf = nil
+	}
+	if t.XDeprecated {
+		t.Deprecated = true
+		t.XDeprecated = false
+	}
+	for _, v := range t.AllOf {
+		openAPISchema(v)
+	}
+	for _, v := range t.OneOf {
+		openAPISchema(v)
+	}
+	if t.XDiscriminator != nil {
+		t.Discriminator = t.XDiscriminator
+		t.XDiscriminator = nil
+		for k, ref := range t.Discriminator.Mapping {
+			t.Discriminator.Mapping[k] = openAPIRef(ref)
+		}
+	}
+	if t.XPropertyNames != nil {
+		t.PropertyNames = t.XPropertyNames
+		t.XPropertyNames = nil
+	}
+	openAPISchema(t.Items)
+	openAPISchema(t.AdditionalProperties)
+	openAPISchema(t.PropertyNames)
+	if t.Properties != nil {
+		for _, k := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(k)
+			if prop, ok := v.(*SwaggerType); ok {
+				openAPISchema(prop)
+			}
+		}
+	}
+}
+
+func openAPIRef(ref string) string {
+	if strings.HasPrefix(ref, SwaggerDefinitionPrefix) {
+		return OpenAPISchemaPrefix + strings.TrimPrefix(ref, SwaggerDefinitionPrefix)
+	}
+	return ref
+}
+
+// OpenAPIDoc is a representation of the top level object in OpenAPI 3.1
+type OpenAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       *SwaggerInfo                            `json:"info"`
+	Servers    []*OpenAPIServer                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths,omitempty"`
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+	Security   []map[string][]string                   `json:"security,omitempty"`
+	Tags       []*SwaggerTag                           `json:"tags,omitempty"`
+}
+
+// OpenAPIServer -
+type OpenAPIServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIComponents -
+type OpenAPIComponents struct {
+	Schemas         map[string]*SwaggerType           `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SwaggerSecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPIOperation -
+type OpenAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses,omitempty"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	XAuthorize  *SwaggerAuthorize           `json:"x-authorize,omitempty"`
+	Extensions  map[string]interface{}      `json:"-"`
+}
+
+// OpenAPIParameter -
+type OpenAPIParameter struct {
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required"`
+	Schema      *SwaggerType           `json:"schema,omitempty"`
+	Example     interface{}            `json:"example,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Extensions  map[string]interface{} `json:"-"`
+}
+
+// OpenAPIRequestBody -
+type OpenAPIRequestBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required"`
+	Content     map[string]*OpenAPIMediaType `json:"content"`
+	Extensions  map[string]interface{}       `json:"-"`
+}
+
+// OpenAPIResponse -
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Headers     map[string]*OpenAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIHeader -
+type OpenAPIHeader struct {
+	Description string       `json:"description,omitempty"`
+	Schema      *SwaggerType `json:"schema"`
+}
+
+// OpenAPIMediaType -
+type OpenAPIMediaType struct {
+	Schema  *SwaggerType `json:"schema,omitempty"`
+	Example interface{}  `json:"example,omitempty"`
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 Yahoo Inc.
+// Licensed under the terms of the Apache license. Please see LICENSE.md file distributed with this work for terms.
+
+package main
+
+//
+// write the generated documents as YAML
+//
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
+)
+
+// marshalSwagger marshals the document in the given format. YAML is converted from the JSON encoding, so both have
+// the same keys in the same order: sorted for maps, as declared for structs and as defined for the properties.
+func marshalSwagger(doc interface{}, format string) ([]byte, error) {
+	j, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil || format == JSONFormat {
+		return j, err
+	}
+	if format != YAMLFormat {
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(j, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// blockStyle drops the flow style and quotes the JSON source left on the nodes, strings that would read as another
+// type are still quoted by the encoder.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
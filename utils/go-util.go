@@ -77,6 +77,7 @@ func formatBlock(s string, leftCol int, rightCol int, prefix string) string {
 		} else {
 			if col == leftCol {
 				col += len(prefix)
+				buf.WriteString(tab)
 				buf.WriteString(prefix)
 			} else {
 				buf.WriteString(" ")
@@ -95,6 +96,18 @@ func FormatComment(s string, leftCol int, rightCol int) string {
 	return formatBlock(s, leftCol, rightCol, "// ")
 }
 
+// FormatJavadoc formats the comment like FormatComment does, as a javadoc block
+func FormatJavadoc(s string, leftCol int, rightCol int) string {
+	if s == "" {
+		return ""
+	}
+	tab := spaces(leftCol)
+	lines := strings.Split(strings.TrimSuffix(formatBlock(s, leftCol, rightCol, " * "), "\n"), "\n")
+	lines[0] = tab + "/**"
+	lines[len(lines)-1] = tab + " */"
+	return strings.Join(lines, "\n") + "\n"
+}
+
 func spaces(count int) string {
 	return stringOfChar(count, ' ')
 }
@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"github.com/ardielle/ardielle-go/rdl"
 	"io"
+	"math"
+	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"text/template"
 	"strconv"
+	"time"
 )
 
 
@@ -37,6 +41,42 @@ func JavaGenerationOrigPackage(schema *rdl.Schema, namespace string) string {
 	return string(schema.Namespace)
 }
 
+// JavaMethodName returns the name of the handler method of a resource: its explicit name if set, else the http method
+// followed by the capitalized path pieces (getUsersById) if usePath is set, or by the body or resource type (postUser).
+func JavaMethodName(r *rdl.Resource, usePath bool) string {
+	if r.Name != "" {
+		return Uncapitalize(string(r.Name))
+	}
+	method := strings.ToLower(r.Method)
+	if !usePath {
+		bodyType := r.Type
+		for _, v := range r.Inputs {
+			if v.Context == "" && v.QueryParam == "" && !v.PathParam && v.Header == "" {
+				bodyType = v.Type
+			}
+		}
+		return method + string(bodyType)
+	}
+	name := method
+	counter := 0
+	for _, piece := range strings.Split(r.Path, "/") {
+		if piece != "" {
+			if strings.Contains(piece, "{") && strings.Contains(piece, "}") {
+				counter++
+				piece = strings.TrimPrefix(piece, "{")
+				piece = strings.TrimSuffix(piece, "}")
+				if counter == 1 {
+					name += "By"
+				} else {
+					name += "And"
+				}
+			}
+			name += Capitalize(piece)
+		}
+	}
+	return name
+}
+
 func JavaGenerationRootPath(schema *rdl.Schema) string {
 	if schema.Base != "" {
 		if schema.Version != nil {
@@ -195,6 +235,37 @@ public final class ParsecErrorDetail implements java.io.Serializable {
 
 const JavaParsecClassSuffix = "_Pc"
 
+const (
+	JavaxNamespace   = "javax"
+	JakartaNamespace = "jakarta"
+	JakartaFlagUsage = "generate jakarta.* imports (Jakarta EE 9+) instead of javax.*"
+)
+
+// javaEEPackages are the javax packages which moved to the jakarta namespace with Jakarta EE 9
+var javaEEPackages = []string{"inject", "servlet", "validation", "ws.rs", "xml.bind"}
+
+// JavaEENamespace returns the root package of the Java EE APIs the generated code imports
+func JavaEENamespace(jakarta bool) string {
+	if jakarta {
+		return JakartaNamespace
+	}
+	return JavaxNamespace
+}
+
+// JavaEEClass moves a class of the Java EE APIs to the jakarta namespace if asked to, other classes are left as is.
+func JavaEEClass(class string, jakarta bool) string {
+	if !jakarta {
+		return class
+	}
+	for _, pkg := range javaEEPackages {
+		prefix := JavaxNamespace + "." + pkg + "."
+		if strings.HasPrefix(class, prefix) {
+			return JakartaNamespace + strings.TrimPrefix(class, JavaxNamespace)
+		}
+	}
+	return class
+}
+
 func JavaGenerateResourceException(schema *rdl.Schema, writer io.Writer, namespace string) error {
 	return _javaGenerateTemplate(schema, writer, javaResourceExceptionTemplate, namespace)
 }
@@ -329,12 +400,16 @@ func JavaType(
 	items rdl.TypeRef,
 	keys rdl.TypeRef,
 	isPcSuffix bool,
-	apiVer int32) string {
+	apiVer int32,
+	genValueTypes bool) string {
 
 	t := reg.FindType(rdlType)
 	if t == nil || t.Variant == 0 {
 		panic("Cannot find type '" + rdlType + "'")
 	}
+	if genValueTypes && IsJavaValueType(reg, t) {
+		return javaClassName(rdlType, isPcSuffix, apiVer)
+	}
 	bt := reg.BaseType(t)
 	switch bt {
 	case rdl.BaseTypeAny:
@@ -388,7 +463,7 @@ func JavaType(
 				i = items
 			}
 		}
-		gitems := JavaType(reg, i, true, "", "", isPcSuffix, apiVer)
+		gitems := JavaType(reg, i, true, "", "", isPcSuffix, apiVer, genValueTypes)
 		//return gitems + "[]" //if arrays, not lists
 		return "List<" + gitems + ">"
 	case rdl.BaseTypeMap:
@@ -406,8 +481,8 @@ func JavaType(
 				i = items
 			}
 		}
-		gkeys := JavaType(reg, k, true, "", "", isPcSuffix, apiVer)
-		gitems := JavaType(reg, i, true, "", "", isPcSuffix, apiVer)
+		gkeys := JavaType(reg, k, true, "", "", isPcSuffix, apiVer, genValueTypes)
+		gitems := JavaType(reg, i, true, "", "", isPcSuffix, apiVer, genValueTypes)
 		return "Map<" + gkeys + "," + gitems + ">"
 	case rdl.BaseTypeStruct:
 		switch t.Variant {
@@ -416,24 +491,284 @@ func JavaType(
 				return "Object"
 			}
 		}
-		javaType := string(rdlType)
-		if apiVer > 1 {
-			javaType += "V" + strconv.Itoa(int(apiVer))
+		return javaClassName(rdlType, isPcSuffix, apiVer)
+	default:
+		return javaClassName(rdlType, isPcSuffix, apiVer)
+	}
+}
+
+func javaClassName(rdlType rdl.TypeRef, isPcSuffix bool, apiVer int32) string {
+	javaType := string(rdlType)
+	if apiVer > 1 {
+		javaType += "V" + strconv.Itoa(int(apiVer))
+	}
+	if isPcSuffix {
+		javaType += JavaParsecClassSuffix
+	}
+	return javaType
+}
+
+// IsJavaValueType tells if the type is a named array, map, string or number type, which can be generated as a
+// Java value type wrapping the underlying List, Map, String or number.
+func IsJavaValueType(reg rdl.TypeRegistry, t *rdl.Type) bool {
+	if t == nil {
+		return false
+	}
+	tName, _, _ := rdl.TypeInfo(t)
+	if reg.IsBaseTypeName(rdl.TypeRef(tName)) {
+		return false
+	}
+	switch t.Variant {
+	case rdl.TypeVariantArrayTypeDef, rdl.TypeVariantMapTypeDef, rdl.TypeVariantStringTypeDef, rdl.TypeVariantNumberTypeDef:
+		return true
+	}
+	return false
+}
+
+// constraintOverrides lists the derived annotations an explicit one replaces, on top of the one with the same key
+var constraintOverrides = map[rdl.ExtendedAnnotation][]rdl.ExtendedAnnotation{
+	"x_min":         {"x_decimal_min"},
+	"x_max":         {"x_decimal_max"},
+	"x_decimal_min": {"x_min"},
+	"x_decimal_max": {"x_max"},
+}
+
+// ConstraintAnnotations returns the annotations to validate a value of the given type with. The constraints derived
+// from the facets of the type and its supertypes come first; the explicit annotations (of a field or a parameter)
+// override them or, if there are none, the x_ annotations of the type do.
+func ConstraintAnnotations(reg rdl.TypeRegistry, rdlType rdl.TypeRef, schemaTypes []*rdl.Type, explicit map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	annotations := make(map[rdl.ExtendedAnnotation]string)
+	t := reg.FindType(rdlType)
+	for visited := make(map[rdl.TypeName]bool); t != nil; {
+		tName, tType, _ := rdl.TypeInfo(t)
+		if visited[tName] || reg.IsBaseTypeName(rdl.TypeRef(tName)) {
+			break
+		}
+		visited[tName] = true
+		for k, v := range TypeFacetAnnotations(t) {
+			if _, ok := annotations[k]; !ok {
+				annotations[k] = v
+			}
 		}
-		if isPcSuffix {
-			javaType += JavaParsecClassSuffix
+		t = reg.FindType(tType)
+	}
+	fromType := len(explicit) == 0
+	if fromType {
+		explicit = GetUserDefinedTypeAnnotations(rdlType, schemaTypes)
+	}
+	for k, v := range explicit {
+		if fromType && (k == DeprecatedAnnotationKey || k == SunsetAnnotationKey) {
+			// the deprecation of a type is not a constraint on its values
+			continue
 		}
-		return javaType
-	default:
-		javaType := string(rdlType)
-		if apiVer > 1 {
-			javaType += "V" + strconv.Itoa(int(apiVer))
+		for _, derived := range constraintOverrides[k] {
+			delete(annotations, derived)
 		}
-		if isPcSuffix {
-			javaType += JavaParsecClassSuffix
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// TypeFacetAnnotations translates the constraints of a typedef (pattern, values, sizes and bounds) into the
+// equivalent extended annotations, so they can be rendered like the x_pattern, x_size, x_min and x_max ones.
+func TypeFacetAnnotations(t *rdl.Type) map[rdl.ExtendedAnnotation]string {
+	annotations := make(map[rdl.ExtendedAnnotation]string)
+	if t == nil {
+		return annotations
+	}
+	switch t.Variant {
+	case rdl.TypeVariantStringTypeDef:
+		typedef := t.StringTypeDef
+		if typedef.Pattern != "" {
+			annotations["x_pattern"] = fmt.Sprintf("regexp = %s", javaStringLiteral(typedef.Pattern))
+		} else if len(typedef.Values) > 0 {
+			values := make([]string, 0, len(typedef.Values))
+			for _, v := range typedef.Values {
+				values = append(values, regexp.QuoteMeta(v))
+			}
+			annotations["x_pattern"] = fmt.Sprintf("regexp = %s", javaStringLiteral("^("+strings.Join(values, "|")+")$"))
 		}
-		return javaType
+		addSizeAnnotation(annotations, nil, typedef.MinSize, typedef.MaxSize)
+	case rdl.TypeVariantNumberTypeDef:
+		typedef := t.NumberTypeDef
+		addNumberAnnotation(annotations, "min", typedef.Min)
+		addNumberAnnotation(annotations, "max", typedef.Max)
+	case rdl.TypeVariantArrayTypeDef:
+		typedef := t.ArrayTypeDef
+		addSizeAnnotation(annotations, typedef.Size, typedef.MinSize, typedef.MaxSize)
+	case rdl.TypeVariantMapTypeDef:
+		typedef := t.MapTypeDef
+		addSizeAnnotation(annotations, typedef.Size, typedef.MinSize, typedef.MaxSize)
+	case rdl.TypeVariantBytesTypeDef:
+		typedef := t.BytesTypeDef
+		addSizeAnnotation(annotations, typedef.Size, typedef.MinSize, typedef.MaxSize)
+	}
+	return annotations
+}
+
+func addSizeAnnotation(annotations map[rdl.ExtendedAnnotation]string, size *int32, minSize *int32, maxSize *int32) {
+	if size != nil {
+		minSize = size
+		maxSize = size
+	}
+	var bounds []string
+	if minSize != nil {
+		bounds = append(bounds, fmt.Sprintf("min = %d", *minSize))
+	}
+	if maxSize != nil {
+		bounds = append(bounds, fmt.Sprintf("max = %d", *maxSize))
+	}
+	if len(bounds) > 0 {
+		annotations["x_size"] = strings.Join(bounds, ", ")
+	}
+}
+
+// addNumberAnnotation uses x_min/x_max for integral bounds, and x_decimal_min/x_decimal_max for floating point ones
+func addNumberAnnotation(annotations map[rdl.ExtendedAnnotation]string, key string, n *rdl.Number) {
+	if n == nil {
+		return
+	}
+	switch n.Variant {
+	case rdl.NumberVariantInt8:
+		annotations[rdl.ExtendedAnnotation("x_"+key)] = fmt.Sprintf("%d", *n.Int8)
+	case rdl.NumberVariantInt16:
+		annotations[rdl.ExtendedAnnotation("x_"+key)] = fmt.Sprintf("%d", *n.Int16)
+	case rdl.NumberVariantInt32:
+		annotations[rdl.ExtendedAnnotation("x_"+key)] = fmt.Sprintf("%d", *n.Int32)
+	case rdl.NumberVariantInt64:
+		if *n.Int64 > math.MaxInt32 || *n.Int64 < math.MinInt32 {
+			annotations[rdl.ExtendedAnnotation("x_"+key)] = fmt.Sprintf("%dL", *n.Int64)
+		} else {
+			annotations[rdl.ExtendedAnnotation("x_"+key)] = fmt.Sprintf("%d", *n.Int64)
+		}
+	case rdl.NumberVariantFloat32:
+		annotations[rdl.ExtendedAnnotation("x_decimal_"+key)] = fmt.Sprintf("\"%g\"", *n.Float32)
+	case rdl.NumberVariantFloat64:
+		annotations[rdl.ExtendedAnnotation("x_decimal_"+key)] = fmt.Sprintf("\"%g\"", *n.Float64)
+	}
+}
+
+func javaStringLiteral(s string) string {
+	return "\"" + strings.Replace(strings.Replace(s, "\\", "\\\\", -1), "\"", "\\\"", -1) + "\""
+}
+
+const (
+	DeprecatedAnnotationKey        = "x_deprecated"
+	SunsetAnnotationKey            = "x_sunset"
+	DeprecatedSymbolsAnnotationKey = "x_deprecated_symbols"
+)
+
+// IsDeprecated tells if the annotations of a type, field, resource or input mark it as deprecated. The value of
+// x_deprecated, if any, is the replacement text.
+func IsDeprecated(annotations map[rdl.ExtendedAnnotation]string) bool {
+	_, ok := annotations[DeprecatedAnnotationKey]
+	return ok
+}
+
+// DeprecationNote explains a deprecation: the replacement text of x_deprecated, followed by the x_sunset date if any.
+func DeprecationNote(annotations map[rdl.ExtendedAnnotation]string) string {
+	note := strings.TrimSpace(annotations[DeprecatedAnnotationKey])
+	if sunset := annotations[SunsetAnnotationKey]; sunset != "" {
+		if note != "" {
+			note = strings.TrimSuffix(note, ".") + ". "
+		}
+		note += "Sunset on " + sunset + "."
+	}
+	return note
+}
+
+// JavaDeprecation returns the @deprecated javadoc and the @Deprecated annotation of a deprecated element, indented
+// by leftCol, or nothing if the element is not deprecated.
+func JavaDeprecation(annotations map[rdl.ExtendedAnnotation]string, leftCol int) string {
+	if !IsDeprecated(annotations) {
+		return ""
+	}
+	return FormatJavadoc(JavaDeprecatedTag(annotations), leftCol, 80) + spaces(leftCol) + "@Deprecated\n"
+}
+
+// JavaDeprecatedTag is the @deprecated javadoc tag of a deprecated element.
+func JavaDeprecatedTag(annotations map[rdl.ExtendedAnnotation]string) string {
+	if note := DeprecationNote(annotations); note != "" {
+		return "@deprecated " + note
+	}
+	return "@deprecated"
+}
+
+// DeprecatedSymbols returns the enum symbols listed in the x_deprecated_symbols annotation of an enum type, since
+// the elements of an enum cannot be annotated themselves.
+func DeprecatedSymbols(annotations map[rdl.ExtendedAnnotation]string) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, sym := range strings.Split(annotations[DeprecatedSymbolsAnnotationKey], ",") {
+		if sym = strings.TrimSpace(sym); sym != "" {
+			symbols[sym] = true
+		}
+	}
+	return symbols
+}
+
+// SunsetHTTPDate formats the x_sunset date as the HTTP-date the Sunset header (RFC 8594) takes. Dates are given as
+// 2006-01-02 or in RFC 3339 form, anything else is used as is.
+func SunsetHTTPDate(sunset string) string {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, sunset); err == nil {
+			return t.UTC().Format(http.TimeFormat)
+		}
+	}
+	return sunset
+}
+
+// InlineDeprecation adds the deprecation of a type that has no Java class of its own (anything but a struct, enum or
+// union) to the annotations of a field of that type, as the field is where the type is inlined.
+func InlineDeprecation(reg rdl.TypeRegistry, rdlType rdl.TypeRef, annotations map[rdl.ExtendedAnnotation]string) map[rdl.ExtendedAnnotation]string {
+	t := reg.FindType(rdlType)
+	if t == nil || IsDeprecated(annotations) || reg.IsBaseTypeName(rdlType) {
+		return annotations
+	}
+	switch reg.BaseType(t) {
+	case rdl.BaseTypeStruct, rdl.BaseTypeEnum, rdl.BaseTypeUnion:
+		return annotations
+	}
+	typeAnnotations := TypeAnnotations(t)
+	if !IsDeprecated(typeAnnotations) {
+		return annotations
+	}
+	merged := make(map[rdl.ExtendedAnnotation]string)
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	merged[DeprecatedAnnotationKey] = typeAnnotations[DeprecatedAnnotationKey]
+	if sunset, ok := typeAnnotations[SunsetAnnotationKey]; ok {
+		merged[SunsetAnnotationKey] = sunset
+	}
+	return merged
+}
+
+// TypeAnnotations returns the x_ annotations of a type definition.
+func TypeAnnotations(t *rdl.Type) map[rdl.ExtendedAnnotation]string {
+	if t == nil {
+		return nil
+	}
+	switch t.Variant {
+	case rdl.TypeVariantAliasTypeDef:
+		return t.AliasTypeDef.Annotations
+	case rdl.TypeVariantStringTypeDef:
+		return t.StringTypeDef.Annotations
+	case rdl.TypeVariantNumberTypeDef:
+		return t.NumberTypeDef.Annotations
+	case rdl.TypeVariantBytesTypeDef:
+		return t.BytesTypeDef.Annotations
+	case rdl.TypeVariantArrayTypeDef:
+		return t.ArrayTypeDef.Annotations
+	case rdl.TypeVariantMapTypeDef:
+		return t.MapTypeDef.Annotations
+	case rdl.TypeVariantStructTypeDef:
+		return t.StructTypeDef.Annotations
+	case rdl.TypeVariantEnumTypeDef:
+		return t.EnumTypeDef.Annotations
+	case rdl.TypeVariantUnionTypeDef:
+		return t.UnionTypeDef.Annotations
 	}
+	return nil
 }
 
 func GetSchemaVersionOrDefault(schema *rdl.Schema, defaultVersion int32) (int32, error) {